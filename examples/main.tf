@@ -1,5 +1,21 @@
 provider "metakube" {
   // Do not forget to set METAKUBE_API_TOKEN environment variable.
+  // Alternatively use METAKUBE_API_TOKEN_FILE, or renew tokens with one of:
+  // refresh_token {
+  //   refresh_token = ""
+  // }
+  // exec {
+  //   command = "metakube-token-helper"
+  //   args    = []
+  // }
+
+  // OpenStack credentials for clusters which don't set their own, optional.
+  // OS_USERNAME/OS_PASSWORD, OS_APPLICATION_CREDENTIAL_ID/OS_APPLICATION_CREDENTIAL_SECRET
+  // or OS_CLOUD with clouds.yaml environment variables are used if the block is not set.
+  // openstack {
+  //   application_credential_id     = ""
+  //   application_credential_secret = ""
+  // }
 }
 
 resource "metakube_project" "my-project" {
@@ -18,8 +34,10 @@ resource "metakube_cluster" "my-cluster" {
   labels = { // has in-place update.
     "environment" = "staging"
   }
+  override_project_labels = [] // optional, keys of project labels set in labels instead of inherited
 
-  sshkeys = [
+  sshkeys = [ // ssh key IDs, has in-place update. Names are deprecated.
+    metakube_sshkey.my-key.id,
   ]
 
   name          = "my-cluster"     // has in-place update
@@ -27,10 +45,25 @@ resource "metakube_cluster" "my-cluster" {
   dc            = "syseleven-dbl1" // openstack datacenter, change forces new
   audit_logging = true             // has in-place update
 
+  deletion_protection    = false           // optional, refuse to destroy or replace the cluster
+  prevent_replacement_of = ["dc", "tenant"] // optional, fail plan instead of replacing the cluster when these change
+
+  delete_volumes_on_destroy        = false // optional, delete volumes of persistent volume claims with the cluster
+  delete_load_balancers_on_destroy = false // optional, delete load balancers of LoadBalancer services with the cluster
+
+  wait_for_rollout = true // optional, wait until node deployment changes reach all nodes. Paused node deployments are not waited for
+
+  timeouts { // optional, how long to wait for cluster and node deployment
+    create = "45m"
+    update = "60m"
+    delete = "15m"
+  }
+
   // openstack 
   tenant            = "" // change forces new
-  provider_username = "" // sensitive, not persisted in tfstate, change forces new
-  provider_password = "" // sensitive, not persisted in tfstate, change forces new
+  provider_username = "" // sensitive, optional if set on provider level, has in-place update
+  provider_password = "" // sensitive, optional if set on provider level, has in-place update
+  // or application_credential_id and application_credential_secret instead of username and password.
 
   // clusters node deployment
   nodedepl {
@@ -42,9 +75,43 @@ resource "metakube_cluster" "my-cluster" {
       max_replicas = 2 // optional, not setting and setting to zero have the same effect.
     }
 
+    paused = false // optional, template changes are not rolled out to nodes while paused. Has in-place update
+
+    rollout_strategy { // optional, has in-place update
+      max_surge       = "1" // number or percentage of nodes, e.g. "25%"
+      max_unavailable = "0" // number or percentage of nodes
+    }
+
     flavor          = "l1.small"                  // has in-place update
     image           = "Rescue Ubuntu 18.04 sys11" // has in-place update
     use_floating_ip = false                       // has in-place update
+
+    disk_size = 50 // optional, root volume size in GB, flavor's local disk if not set. Has in-place update
+    // root_volume_type  = "" // optional, requires disk_size. Has in-place update
+    // availability_zone = "" // optional, has in-place update
+    tags = { // optional, instance tags, has in-place update
+      "cost-center" = "dev"
+    }
+    node_labels = { // optional, kubernetes labels of nodes, has in-place update
+      "role" = "worker"
+    }
+
+    // taints { // optional, repeatable, has in-place update
+    //   key    = "dedicated"
+    //   value  = "ingress"
+    //   effect = "NoSchedule" // NoSchedule, PreferNoSchedule or NoExecute
+    // }
+
+    // kubelet { // optional, has in-place update
+    //   max_pods      = 110
+    //   eviction_hard = { "memory.available" = "100Mi" }
+    // }
+
+    operating_system { // optional, ubuntu if not set. Has in-place update, nodes are replaced one by one.
+      name                 = "ubuntu" // ubuntu, centos, container_linux or flatcar, must match the image
+      dist_upgrade_on_boot = false    // ubuntu and centos only
+      // disable_auto_update = false  // container_linux and flatcar only
+    }
   }
 }
 
@@ -55,3 +122,15 @@ resource "metakube_sshkey" "my-key" {
 
   public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCut5oRyqeqYci3E9m6Z6mtxfqkiyb+xNFJM6+/sllhnMDX0vzrNj8PuIFfGkgtowKY//QWLgoB+RpvXqcD4bb4zPkLdXdJPtUf1eAoMh/qgyThUjBs3n7BXvXMDg1Wdj0gq/sTnPLvXsfrSVPjiZvWN4h0JdID2NLnwYuKIiltIn+IbUa6OnyFfOEpqb5XJ7H7LK1mUKTlQ/9CFROxSQf3YQrR9UdtASIeyIZL53WgYgU31Yqy7MQaY1y0fGmHsFwpCK6qFZj1DNruKl/IR1lLx/Bg3z9sDcoBnHKnzSzVels9EVlDOG6bW738ho269QAIrWQYBtznsvWKu5xZPuuj user@machine"
 }
+
+output "api_url" {
+  value = metakube_cluster.my-cluster.api_url
+}
+
+output "actual_version" {
+  value = metakube_cluster.my-cluster.actual_version // also available: created_at, seed, type
+}
+
+output "effective_labels" {
+  value = metakube_cluster.my-cluster.effective_labels // own labels and inherited_labels from project
+}
@@ -8,5 +8,7 @@ require (
 	github.com/go-openapi/strfmt v0.19.4 // indirect
 	github.com/hashicorp/terraform-plugin-sdk v1.6.0
 	github.com/pkg/errors v0.9.1
+	golang.org/x/crypto v0.0.0-20200220183623-bac4c82f6975
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
+	gopkg.in/yaml.v2 v2.2.4
 )
@@ -0,0 +1,75 @@
+package gometakube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AddonsService handles communication with cluster addons related endpoints.
+type AddonsService struct {
+	client *Client
+}
+
+func clusterInstallableAddonsPath(prj, dc, cls string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/installableaddons", prj, dc, cls)
+}
+
+func clusterAddonsPath(prj, dc, cls string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons", prj, dc, cls)
+}
+
+func clusterAddonResourcePath(prj, dc, cls, id string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons/%s", prj, dc, cls, id)
+}
+
+// Installable returns names of addons possible to install into a cluster.
+func (svc *AddonsService) Installable(ctx context.Context, prj, dc, cls string) ([]string, *http.Response, error) {
+	ret := make([]string, 0)
+	resp, err := svc.client.resourceList(ctx, clusterInstallableAddonsPath(prj, dc, cls), &ret)
+	return ret, resp, err
+}
+
+// List returns list of addons installed in a cluster.
+func (svc *AddonsService) List(ctx context.Context, prj, dc, cls string) ([]Addon, *http.Response, error) {
+	ret := make([]Addon, 0)
+	resp, err := svc.client.resourceList(ctx, clusterAddonsPath(prj, dc, cls), &ret)
+	return ret, resp, err
+}
+
+// Create installs an addon into a cluster.
+func (svc *AddonsService) Create(ctx context.Context, prj, dc, cls string, create *Addon) (*Addon, *http.Response, error) {
+	ret := new(Addon)
+	resp, err := svc.client.resourceCreate(ctx, clusterAddonsPath(prj, dc, cls), create, ret)
+	return ret, resp, err
+}
+
+// Get returns cluster addon.
+func (svc *AddonsService) Get(ctx context.Context, prj, dc, cls, id string) (*Addon, *http.Response, error) {
+	ret := new(Addon)
+	resp, err := svc.client.resourceGet(ctx, clusterAddonResourcePath(prj, dc, cls, id), ret)
+	return ret, resp, err
+}
+
+// Patch updates cluster addon variables.
+func (svc *AddonsService) Patch(ctx context.Context, prj, dc, cls, id string, patch *Addon) (*Addon, *http.Response, error) {
+	ret := new(Addon)
+	resp, err := svc.client.resourcePatch(ctx, clusterAddonResourcePath(prj, dc, cls, id), patch, ret)
+	return ret, resp, err
+}
+
+// MergePatch updates cluster addon with merge patch turning from into to, variables missing in to are removed.
+func (svc *AddonsService) MergePatch(ctx context.Context, prj, dc, cls, id string, from, to *Addon) (*Addon, *http.Response, error) {
+	patch, err := NewMergePatch(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret := new(Addon)
+	resp, err := svc.client.resourcePatch(ctx, clusterAddonResourcePath(prj, dc, cls, id), patch, ret)
+	return ret, resp, err
+}
+
+// Delete uninstalls addon from a cluster.
+func (svc *AddonsService) Delete(ctx context.Context, prj, dc, cls, id string) (*http.Response, error) {
+	return svc.client.resourceDelete(ctx, clusterAddonResourcePath(prj, dc, cls, id))
+}
@@ -0,0 +1,49 @@
+package gometakube
+
+import "time"
+
+type Addon struct {
+	CreationTimestamp *time.Time   `json:"creationTimestamp,omitempty"`
+	DeletionTimestamp *time.Time   `json:"deletionTimestamp,omitempty"`
+	ID                string       `json:"id,omitempty"`
+	Name              string       `json:"name"`
+	Generation        uint         `json:"generation,omitempty"`
+	Spec              AddonSpec    `json:"spec"`
+	Status            *AddonStatus `json:"status,omitempty"`
+}
+
+type AddonSpec struct {
+	IsDefault bool                   `json:"isDefault,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// AddonStatus is reported by addon controller.
+type AddonStatus struct {
+	ObservedGeneration uint             `json:"observedGeneration"`
+	Conditions         []AddonCondition `json:"conditions,omitempty"`
+}
+
+// AddonConditionReconciled is true when manifests of the addon are applied to the cluster.
+const AddonConditionReconciled = "AddonReconciledSuccessfully"
+
+// AddonCondition describes state of the addon.
+type AddonCondition struct {
+	Type               string     `json:"type"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// Condition returns condition of given type, nil if it is not reported.
+func (s *AddonStatus) Condition(t string) *AddonCondition {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package gometakube
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const addonJSON = `{
+  "creationTimestamp": "2020-04-02T12:00:00Z",
+  "id": "dashboard",
+  "name": "dashboard",
+  "generation": 2,
+  "spec": {
+	"variables": {
+	  "replicas": "2"
+	}
+  },
+  "status": {
+	"observedGeneration": 2,
+	"conditions": [
+	  {"type": "AddonReconciledSuccessfully", "status": "True"}
+	]
+  }
+}`
+
+var addon = Addon{
+	CreationTimestamp: testParseTime("2020-04-02T12:00:00Z"),
+	ID:                "dashboard",
+	Name:              "dashboard",
+	Generation:        2,
+	Spec: AddonSpec{
+		Variables: map[string]interface{}{
+			"replicas": "2",
+		},
+	},
+	Status: &AddonStatus{
+		ObservedGeneration: 2,
+		Conditions: []AddonCondition{
+			{Type: AddonConditionReconciled, Status: "True"},
+		},
+	},
+}
+
+func TestAddons_Installable(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/installableaddons", prj, dc, cls)
+	want := []string{"dashboard", "node-exporter"}
+	testResourceList(t, `["dashboard", "node-exporter"]`, path, want, func() (interface{}, error) {
+		l, _, err := client.Addons.Installable(ctx, prj, dc, cls)
+		return l, err
+	})
+}
+
+func TestAddons_List(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons", prj, dc, cls)
+	want := []Addon{addon}
+	testResourceList(t, "["+addonJSON+"]", path, want, func() (interface{}, error) {
+		l, _, err := client.Addons.List(ctx, prj, dc, cls)
+		return l, err
+	})
+}
+
+func TestAddons_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	create := &Addon{
+		Name: "dashboard",
+		Spec: AddonSpec{Variables: map[string]interface{}{"replicas": "2"}},
+	}
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons", prj, dc, cls)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		v := new(Addon)
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, create) {
+			t.Fatalf("want: %+v, got: %+v", create, v)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, addonJSON)
+	})
+
+	got, _, err := client.Addons.Create(ctx, prj, dc, cls, create)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &addon) {
+		t.Fatalf("want: %+v, got: %+v", addon, got)
+	}
+}
+
+func TestAddons_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons/%s", prj, dc, cls, addon.ID)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, addonJSON)
+	})
+
+	got, _, err := client.Addons.Get(ctx, prj, dc, cls, addon.ID)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &addon) {
+		t.Fatalf("want: %+v, got: %+v", addon, got)
+	}
+}
+
+func TestAddons_Patch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons/%s", prj, dc, cls, addon.ID)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		fmt.Fprint(w, addonJSON)
+	})
+
+	got, _, err := client.Addons.Patch(ctx, prj, dc, cls, addon.ID, &addon)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &addon) {
+		t.Fatalf("want: %+v, got: %+v", addon, got)
+	}
+}
+
+func TestAddons_MergePatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons/%s", prj, dc, cls, addon.ID)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		b, err := ioutil.ReadAll(r.Body)
+		testErrNil(t, err)
+		if want, got := `{"spec":{"variables":{"removed":null}}}`+"\n", string(b); want != got {
+			t.Fatalf("want body: %s, got: %s", want, got)
+		}
+		fmt.Fprint(w, addonJSON)
+	})
+
+	from := &Addon{Name: "theaddon", Spec: AddonSpec{Variables: map[string]interface{}{"kept": "1", "removed": "2"}}}
+	to := &Addon{Name: "theaddon", Spec: AddonSpec{Variables: map[string]interface{}{"kept": "1"}}}
+	got, _, err := client.Addons.MergePatch(ctx, prj, dc, cls, addon.ID, from, to)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &addon) {
+		t.Fatalf("want: %+v, got: %+v", addon, got)
+	}
+}
+
+func TestAddons_Delete(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/addons/%s", prj, dc, cls, addon.ID)
+	testResourceDelete(t, path, func() error {
+		_, err := client.Addons.Delete(ctx, prj, dc, cls, addon.ID)
+		return err
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 )
 
 func clustersListPath(prj string) string {
@@ -22,6 +25,14 @@ func clusterResourceHealthPath(prj, dc, clusterID string) string {
 	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/health", prj, dc, clusterID)
 }
 
+func clusterEventsPath(prj, dc, clusterID string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/events", prj, dc, clusterID)
+}
+
+func clusterMetricsPath(prj, dc, clusterID string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/metrics", prj, dc, clusterID)
+}
+
 func clusterUpgradesPath(prj, dc, clusterID string) string {
 	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/upgrades", prj, dc, clusterID)
 }
@@ -57,6 +68,28 @@ func (svc *ClustersService) Delete(ctx context.Context, prj, dc, clusterID strin
 	return svc.client.resourceDelete(ctx, path)
 }
 
+// ClusterDeleteOptions selects cloud resources created by cluster workloads to delete along with the cluster.
+type ClusterDeleteOptions struct {
+	// DeleteVolumes deletes volumes of persistent volume claims.
+	DeleteVolumes bool
+	// DeleteLoadBalancers deletes load balancers of LoadBalancer services.
+	DeleteLoadBalancers bool
+}
+
+// DeleteWithCleanup deletes cluster and cloud resources selected in opts.
+func (svc *ClustersService) DeleteWithCleanup(ctx context.Context, prj, dc, clusterID string, opts *ClusterDeleteOptions) (*http.Response, error) {
+	path := clusterResourcePath(prj, dc, clusterID)
+	req, err := svc.client.NewRequest(http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if opts != nil {
+		req.Header.Set("DeleteVolumes", strconv.FormatBool(opts.DeleteVolumes))
+		req.Header.Set("DeleteLoadBalancers", strconv.FormatBool(opts.DeleteLoadBalancers))
+	}
+	return svc.client.Do(ctx, req, nil)
+}
+
 // Get returns cluster details.
 func (svc *ClustersService) Get(ctx context.Context, prj, dc, clusterID string) (*Cluster, *http.Response, error) {
 	path := clusterResourcePath(prj, dc, clusterID)
@@ -75,8 +108,22 @@ type PatchClusterRequest struct {
 
 // PatchClusterRequestSpec fields allowed to change on cluster spec in place.
 type PatchClusterRequestSpec struct {
-	Version      string                   `json:"version,omitempty"`
-	AuditLogging *ClusterSpecAuditLogging `json:"auditLogging,omitempty"`
+	Version      string                        `json:"version,omitempty"`
+	AuditLogging *ClusterSpecAuditLogging      `json:"auditLogging,omitempty"`
+	Cloud        *PatchClusterRequestSpecCloud `json:"cloud,omitempty"`
+}
+
+// PatchClusterRequestSpecCloud cloud fields allowed to change in place.
+type PatchClusterRequestSpecCloud struct {
+	OpenStack *PatchClusterRequestSpecCloudOpenstack `json:"openstack,omitempty"`
+}
+
+// PatchClusterRequestSpecCloudOpenstack openstack credentials to rotate in place.
+type PatchClusterRequestSpecCloudOpenstack struct {
+	ApplicationCredentialID     string `json:"applicationCredentialID,omitempty"`
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
+	Password                    string `json:"password,omitempty"`
+	Username                    string `json:"username,omitempty"`
 }
 
 // Patch updates cluster.
@@ -87,11 +134,59 @@ func (svc *ClustersService) Patch(ctx context.Context, prj, dc, clusterID string
 	return ret, resp, err
 }
 
+// MergePatch updates cluster with merge patch turning from into to, fields missing in to are removed.
+func (svc *ClustersService) MergePatch(ctx context.Context, prj, dc, clusterID string, from, to *PatchClusterRequest) (*Cluster, *http.Response, error) {
+	patch, err := NewMergePatch(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := clusterResourcePath(prj, dc, clusterID)
+	ret := new(Cluster)
+	resp, err := svc.client.resourcePatch(ctx, path, patch, ret)
+	return ret, resp, err
+}
+
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthStatusDown:
+		return "down"
+	case HealthStatusUp:
+		return "up"
+	case HealthStatusProvisioning:
+		return "provisioning"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// Components returns status of every cluster component keyed by its api name.
+func (h *ClusterHealth) Components() map[string]HealthStatus {
+	return map[string]HealthStatus{
+		"apiserver":                    h.APIServer,
+		"cloudProviderInfrastructure":  h.CloudProviderInfrastructure,
+		"controller":                   h.Controller,
+		"etcd":                         h.Etcd,
+		"machineController":            h.MachineController,
+		"scheduler":                    h.Scheduler,
+		"userClusterControllerManager": h.UserClusterControllerManager,
+	}
+}
+
 // Healthy returns whether all cluster components are ready.
 func (h *ClusterHealth) Healthy() bool {
-	return (h.APIServer & h.CloudProviderInfrastructure &
-		h.Controller & h.Etcd & h.MachineController &
-		h.Scheduler & h.UserClusterControllerManager) == 1
+	return len(h.NotReady()) == 0
+}
+
+// NotReady returns sorted names of components which are not up, with their status, e.g. "etcd: provisioning".
+func (h *ClusterHealth) NotReady() []string {
+	ret := make([]string, 0)
+	for name, status := range h.Components() {
+		if status != HealthStatusUp {
+			ret = append(ret, fmt.Sprintf("%s: %s", name, status))
+		}
+	}
+	sort.Strings(ret)
+	return ret
 }
 
 // Health requests cluster's helth.
@@ -106,6 +201,33 @@ func (svc *ClustersService) Health(ctx context.Context, prj, dc, id string) (*Cl
 	return ret, resp, err
 }
 
+// Event types to filter events by.
+const (
+	EventTypeNormal  = "normal"
+	EventTypeWarning = "warning"
+)
+
+// Events returns cluster's events, all types are returned if eventType is empty.
+func (svc *ClustersService) Events(ctx context.Context, prj, dc, id, eventType string) ([]Event, *http.Response, error) {
+	ret := make([]Event, 0)
+	resp, err := svc.client.resourceList(ctx, eventsPath(clusterEventsPath(prj, dc, id), eventType), &ret)
+	return ret, resp, err
+}
+
+func eventsPath(path, eventType string) string {
+	if eventType == "" {
+		return path
+	}
+	return path + "?type=" + url.QueryEscape(eventType)
+}
+
+// Metrics returns resource usage of cluster's control plane and nodes.
+func (svc *ClustersService) Metrics(ctx context.Context, prj, dc, id string) (*ClusterMetrics, *http.Response, error) {
+	ret := new(ClusterMetrics)
+	resp, err := svc.client.resourceGet(ctx, clusterMetricsPath(prj, dc, id), ret)
+	return ret, resp, err
+}
+
 // ClusterUpgrade is a cluster version possible to upgrade into.
 type ClusterUpgrade struct {
 	Version string `json:"version"`
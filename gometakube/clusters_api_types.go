@@ -110,18 +110,20 @@ type ClusterSpecCloudKubevirt struct {
 }
 
 type ClusterSpecCloudOpenstack struct {
-	CredentialsReference *ClusterSpecCloudCredentialReference `json:"credentialsReference"`
-	Domain               string                               `json:"domain"`
-	FloatingIPPool       string                               `json:"floatingIpPool"`
-	Network              string                               `json:"network"`
-	Password             string                               `json:"password"`
-	RouterID             string                               `json:"routerID,omitempty"`
-	SecurityGroups       string                               `json:"securityGroups"`
-	SubnetCIDR           string                               `json:"subnetCIDR,omitempty"`
-	SubnetID             string                               `json:"subnetID"`
-	Tenant               string                               `json:"tenant"`
-	TenantID             string                               `json:"tenantID"`
-	Username             string                               `json:"username"`
+	ApplicationCredentialID     string                               `json:"applicationCredentialID,omitempty"`
+	ApplicationCredentialSecret string                               `json:"applicationCredentialSecret,omitempty"`
+	CredentialsReference        *ClusterSpecCloudCredentialReference `json:"credentialsReference"`
+	Domain                      string                               `json:"domain"`
+	FloatingIPPool              string                               `json:"floatingIpPool"`
+	Network                     string                               `json:"network"`
+	Password                    string                               `json:"password"`
+	RouterID                    string                               `json:"routerID,omitempty"`
+	SecurityGroups              string                               `json:"securityGroups"`
+	SubnetCIDR                  string                               `json:"subnetCIDR,omitempty"`
+	SubnetID                    string                               `json:"subnetID"`
+	Tenant                      string                               `json:"tenant"`
+	TenantID                    string                               `json:"tenantID"`
+	Username                    string                               `json:"username"`
 }
 
 type ClusterSpecCloudPacket struct {
@@ -201,11 +203,58 @@ type ClusterSpecUpdateWindow struct {
 }
 
 type ClusterHealth struct {
-	APIServer                    uint8 `json:"apiserver"`
-	CloudProviderInfrastructure  uint8 `json:"cloudProviderInfrastructure"`
-	Controller                   uint8 `json:"controller"`
-	Etcd                         uint8 `json:"etcd"`
-	MachineController            uint8 `json:"machineController"`
-	Scheduler                    uint8 `json:"scheduler"`
-	UserClusterControllerManager uint8 `json:"userClusterControllerManager"`
+	APIServer                    HealthStatus `json:"apiserver"`
+	CloudProviderInfrastructure  HealthStatus `json:"cloudProviderInfrastructure"`
+	Controller                   HealthStatus `json:"controller"`
+	Etcd                         HealthStatus `json:"etcd"`
+	MachineController            HealthStatus `json:"machineController"`
+	Scheduler                    HealthStatus `json:"scheduler"`
+	UserClusterControllerManager HealthStatus `json:"userClusterControllerManager"`
+}
+
+// HealthStatus is a state of a cluster component.
+type HealthStatus uint8
+
+// Cluster component states.
+const (
+	HealthStatusDown         HealthStatus = 0
+	HealthStatusUp           HealthStatus = 1
+	HealthStatusProvisioning HealthStatus = 2
+)
+
+type Event struct {
+	ID                string              `json:"id,omitempty"`
+	Name              string              `json:"name"`
+	CreationTimestamp *time.Time          `json:"creationTimestamp,omitempty"`
+	LastTimestamp     *time.Time          `json:"lastTimestamp,omitempty"`
+	Count             uint                `json:"count"`
+	Message           string              `json:"message"`
+	Type              string              `json:"type"`
+	InvolvedObject    EventInvolvedObject `json:"involvedObject"`
+}
+
+type EventInvolvedObject struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Type      string `json:"type"`
+}
+
+type ClusterMetrics struct {
+	Name         string              `json:"name"`
+	ControlPlane ControlPlaneMetrics `json:"controlPlane"`
+	Nodes        NodesMetric         `json:"nodes"`
+}
+
+type ControlPlaneMetrics struct {
+	MemoryTotalBytes   int64 `json:"memoryTotalBytes"`
+	CPUTotalMillicores int64 `json:"cpuTotalMillicores"`
+}
+
+type NodesMetric struct {
+	MemoryTotalBytes       int64 `json:"memoryTotalBytes"`
+	MemoryAvailableBytes   int64 `json:"memoryAvailableBytes"`
+	MemoryUsedPercentage   int64 `json:"memoryUsedPercentage"`
+	CPUTotalMillicores     int64 `json:"cpuTotalMillicores"`
+	CPUAvailableMillicores int64 `json:"cpuAvailableMillicores"`
+	CPUUsedPercentage      int64 `json:"cpuUsedPercentage"`
 }
@@ -3,6 +3,7 @@ package gometakube
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"reflect"
 	"testing"
@@ -138,6 +139,30 @@ func TestClusters_Delete(t *testing.T) {
 	})
 }
 
+func TestClusters_DeleteWithCleanup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s", "the-proj", "bk11", "the-cluster")
+	sentDelete := false
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		if want, got := "true", r.Header.Get("DeleteVolumes"); want != got {
+			t.Fatalf("want DeleteVolumes header: %s, got: %s", want, got)
+		}
+		if want, got := "false", r.Header.Get("DeleteLoadBalancers"); want != got {
+			t.Fatalf("want DeleteLoadBalancers header: %s, got: %s", want, got)
+		}
+		sentDelete = true
+	})
+
+	_, err := client.Clusters.DeleteWithCleanup(ctx, "the-proj", "bk11", "the-cluster", &ClusterDeleteOptions{DeleteVolumes: true})
+	testErrNil(t, err)
+	if !sentDelete {
+		t.Fatal("delete request was not sent")
+	}
+}
+
 func TestClusters_Get(t *testing.T) {
 	setup()
 	defer teardown()
@@ -188,6 +213,41 @@ func TestClusters_Patch(t *testing.T) {
 	}
 }
 
+func TestClusters_MergePatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	prj := "the-prj"
+	dc := "thedc"
+	cls := "thecluster"
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s", prj, dc, cls)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		if want, got := MergePatchContentType, r.Header.Get("Content-Type"); want != got {
+			t.Fatalf("want content type: %s, got: %s", want, got)
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		testErrNil(t, err)
+		if want, got := `{"labels":{"newkey":"newvalue","oldkey":null}}`+"\n", string(b); want != got {
+			t.Fatalf("want body: %s, got: %s", want, got)
+		}
+		fmt.Fprint(w, clusterJSON)
+	})
+	from := &PatchClusterRequest{
+		Name:   "thecluster",
+		Labels: map[string]string{"oldkey": "oldvalue"},
+	}
+	to := &PatchClusterRequest{
+		Name:   "thecluster",
+		Labels: map[string]string{"newkey": "newvalue"},
+	}
+	got, _, err := client.Clusters.MergePatch(ctx, prj, dc, cls, from, to)
+	testErrNil(t, err)
+	if want := &cluster; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %v, got: %v", want, got)
+	}
+}
+
 const clusterHealthJSON = `
   {
 	"apiserver": 0,
@@ -270,3 +330,126 @@ func TestClusters_Upgrade(t *testing.T) {
 		t.Fatalf("want cluster upgrades: %v, got: %v", want, got)
 	}
 }
+
+const eventJSON = `
+  {
+	"id": "event-id",
+	"name": "thecluster.15f",
+	"creationTimestamp": "2020-04-03T10:00:00Z",
+	"lastTimestamp": "2020-04-03T10:05:00Z",
+	"count": 3,
+	"message": "failed to create floating ip",
+	"type": "Warning",
+	"involvedObject": {
+	  "name": "thecluster",
+	  "namespace": "cluster-thecluster",
+	  "type": "Cluster"
+	}
+  }
+`
+
+var event = Event{
+	ID:                "event-id",
+	Name:              "thecluster.15f",
+	CreationTimestamp: testParseTime("2020-04-03T10:00:00Z"),
+	LastTimestamp:     testParseTime("2020-04-03T10:05:00Z"),
+	Count:             3,
+	Message:           "failed to create floating ip",
+	Type:              "Warning",
+	InvolvedObject: EventInvolvedObject{
+		Name:      "thecluster",
+		Namespace: "cluster-thecluster",
+		Type:      "Cluster",
+	},
+}
+
+func TestClusters_Events(t *testing.T) {
+	setup()
+	defer teardown()
+
+	prj := "the-proj"
+	dc := "thedc"
+	cls := "thecluster"
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/events", prj, dc, cls)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if want, got := EventTypeWarning, r.URL.Query().Get("type"); want != got {
+			t.Fatalf("want type filter: %s, got: %s", want, got)
+		}
+		fmt.Fprint(w, "["+eventJSON+"]")
+	})
+
+	got, _, err := client.Clusters.Events(ctx, prj, dc, cls, EventTypeWarning)
+	testErrNil(t, err)
+	if want := []Event{event}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %+v, got: %+v", want, got)
+	}
+}
+
+func TestClusterHealth_NotReady(t *testing.T) {
+	h := ClusterHealth{
+		APIServer:                    HealthStatusUp,
+		CloudProviderInfrastructure:  HealthStatusUp,
+		Controller:                   HealthStatusUp,
+		Etcd:                         HealthStatusProvisioning,
+		MachineController:            HealthStatusDown,
+		Scheduler:                    HealthStatusUp,
+		UserClusterControllerManager: HealthStatusUp,
+	}
+	if h.Healthy() {
+		t.Fatalf("want not healthy: %+v", h)
+	}
+	want := []string{"etcd: provisioning", "machineController: down"}
+	if got := h.NotReady(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want not ready: %v, got: %v", want, got)
+	}
+
+	h.Etcd = HealthStatusUp
+	h.MachineController = HealthStatusUp
+	if !h.Healthy() {
+		t.Fatalf("want healthy, not ready: %v", h.NotReady())
+	}
+}
+
+func TestClusters_Metrics(t *testing.T) {
+	setup()
+	defer teardown()
+
+	prj := "the-proj"
+	dc := "thedc"
+	cls := "thecluster"
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/metrics", prj, dc, cls)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"name": "thecluster",
+			"controlPlane": {"memoryTotalBytes": 1024, "cpuTotalMillicores": 500},
+			"nodes": {
+				"memoryTotalBytes": 4096,
+				"memoryAvailableBytes": 1024,
+				"memoryUsedPercentage": 75,
+				"cpuTotalMillicores": 2000,
+				"cpuAvailableMillicores": 1500,
+				"cpuUsedPercentage": 25
+			}
+		}`)
+	})
+
+	got, _, err := client.Clusters.Metrics(ctx, prj, dc, cls)
+	testErrNil(t, err)
+	want := &ClusterMetrics{
+		Name:         "thecluster",
+		ControlPlane: ControlPlaneMetrics{MemoryTotalBytes: 1024, CPUTotalMillicores: 500},
+		Nodes: NodesMetric{
+			MemoryTotalBytes:       4096,
+			MemoryAvailableBytes:   1024,
+			MemoryUsedPercentage:   75,
+			CPUTotalMillicores:     2000,
+			CPUAvailableMillicores: 1500,
+			CPUUsedPercentage:      25,
+		},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %+v, got: %+v", want, got)
+	}
+}
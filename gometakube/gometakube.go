@@ -22,7 +22,7 @@ type Client struct {
 	client  *http.Client
 	BaseURL *url.URL
 
-	// retry patch request on conflict status node 409.
+	// retry unconditional patch request on conflict status code 409.
 	retriesOnConflict     uint
 	retryOnConflictPeriod time.Duration
 
@@ -33,6 +33,9 @@ type Client struct {
 	NodeDeployments *NodeDeploymentsService
 	Openstack       *OpenstackService
 	SSHKeys         *SSHKeysService
+	ServiceAccounts *ServiceAccountsService
+	Addons          *AddonsService
+	RBAC            *RBACService
 }
 
 // An ErrorMessage details the error caused by an API request.
@@ -95,9 +98,7 @@ func (t *tokenSource) Token() (*oauth2.Token, error) {
 
 // WithBearerToken used for api client with Bearer Authentication.
 func WithBearerToken(token string) CreateOpt {
-	return func() *http.Client {
-		return oauth2.NewClient(context.Background(), &tokenSource{token})
-	}
+	return WithTokenSource(&tokenSource{token})
 }
 
 // WithDefault used to create api client with default http client.
@@ -128,6 +129,9 @@ func NewClient(opt CreateOpt) *Client {
 	client.NodeDeployments = &NodeDeploymentsService{client}
 	client.Openstack = &OpenstackService{client}
 	client.SSHKeys = &SSHKeysService{client}
+	client.ServiceAccounts = &ServiceAccountsService{client}
+	client.Addons = &AddonsService{client}
+	client.RBAC = &RBACService{client}
 
 	return client
 }
@@ -214,29 +218,34 @@ func (c *Client) resourcePut(ctx context.Context, path string, put, ret interfac
 	if err != nil {
 		return nil, err
 	}
+	if etag := ifMatch(ctx); etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
 	return c.Do(ctx, req, ret)
 }
 
+// resourcePatch retries on conflict only patches without If-Match precondition,
+// conditional patch failing on conflict is returned to the caller to resolve.
 func (c *Client) resourcePatch(ctx context.Context, path string, patch, ret interface{}) (*http.Response, error) {
-	req, err := c.NewRequest(http.MethodPatch, path, patch)
-	if err != nil {
-		return nil, err
-	}
-	// TODO(furkhat): move retries out.
-	ticker := time.NewTicker(c.retryOnConflictPeriod)
-	defer ticker.Stop()
-	resp, err := c.Do(ctx, req, &ret)
-	for i := uint(0); i < c.retriesOnConflict; i++ {
+	etag := ifMatch(ctx)
+	for i := uint(0); ; i++ {
+		req, err := c.NewRequest(http.MethodPatch, path, patch)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", MergePatchContentType)
+		if etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
+		resp, err := c.Do(ctx, req, ret)
+		if etag != "" || i >= c.retriesOnConflict || !IsConflict(err) {
+			return resp, err
+		}
+		// TODO(furkhat): add warning log.
 		select {
-		case <-ticker.C:
-			if resp != nil && (resp.StatusCode < 400 || resp.StatusCode > 499) {
-				break
-			}
-			// TODO(furkhat): add warning log.
-			resp, err = c.Do(ctx, req, &ret)
+		case <-time.After(c.retryOnConflictPeriod):
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
 	}
-	return resp, err
 }
@@ -104,6 +104,10 @@ func testParseTime(s string) *time.Time {
 	return &ret
 }
 
+func testIntPtr(v int) *int {
+	return &v
+}
+
 func testResourceList(t *testing.T, listJSON, path string, want interface{}, call func() (interface{}, error)) {
 	t.Helper()
 
@@ -0,0 +1,71 @@
+package gometakube
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// MergePatchContentType is a content type of JSON merge patch, RFC 7386.
+const MergePatchContentType = "application/merge-patch+json"
+
+// MergePatch is a JSON merge patch, RFC 7386.
+// Fields set to nil are removed by the api.
+type MergePatch map[string]interface{}
+
+// NewMergePatch returns merge patch turning from into to.
+// Both are encoded to json first, so json tags decide what is compared.
+// Fields missing in to are removed, nil from is treated as empty object.
+func NewMergePatch(from, to interface{}) (MergePatch, error) {
+	oldMap, err := jsonObject(from)
+	if err != nil {
+		return nil, err
+	}
+	newMap, err := jsonObject(to)
+	if err != nil {
+		return nil, err
+	}
+	return mergePatchObjects(oldMap, newMap), nil
+}
+
+// Empty reports whether patch changes nothing.
+func (p MergePatch) Empty() bool {
+	return len(p) == 0
+}
+
+func jsonObject(v interface{}) (map[string]interface{}, error) {
+	ret := make(map[string]interface{})
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return ret, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func mergePatchObjects(old, new map[string]interface{}) MergePatch {
+	ret := make(MergePatch)
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			ret[k] = nil
+		}
+	}
+	for k, newValue := range new {
+		oldValue, ok := old[k]
+		if ok && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		oldObject, oldIsObject := oldValue.(map[string]interface{})
+		newObject, newIsObject := newValue.(map[string]interface{})
+		if ok && oldIsObject && newIsObject {
+			ret[k] = mergePatchObjects(oldObject, newObject)
+			continue
+		}
+		ret[k] = newValue
+	}
+	return ret
+}
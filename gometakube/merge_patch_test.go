@@ -0,0 +1,51 @@
+package gometakube
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewMergePatch(t *testing.T) {
+	old := &PatchClusterRequest{
+		Name:   "cluster",
+		Labels: map[string]string{"keep": "v", "change": "old", "remove": "v"},
+		Spec: &PatchClusterRequestSpec{
+			AuditLogging: &ClusterSpecAuditLogging{Enabled: true},
+		},
+	}
+	to := &PatchClusterRequest{
+		Name:   "cluster",
+		Labels: map[string]string{"keep": "v", "change": "new"},
+		Spec: &PatchClusterRequestSpec{
+			AuditLogging: &ClusterSpecAuditLogging{Enabled: true},
+		},
+	}
+	patch, err := NewMergePatch(old, to)
+	testErrNil(t, err)
+	b, err := json.Marshal(patch)
+	testErrNil(t, err)
+	if want, got := `{"labels":{"change":"new","remove":null}}`, string(b); want != got {
+		t.Fatalf("want patch: %s, got: %s", want, got)
+	}
+
+	to.Labels = nil
+	patch, err = NewMergePatch(old, to)
+	testErrNil(t, err)
+	b, err = json.Marshal(patch)
+	testErrNil(t, err)
+	if want, got := `{"labels":null}`, string(b); want != got {
+		t.Fatalf("want all labels removed: %s, got: %s", want, got)
+	}
+
+	patch, err = NewMergePatch(old, old)
+	testErrNil(t, err)
+	if !patch.Empty() {
+		t.Fatalf("want empty patch, got: %v", patch)
+	}
+
+	patch, err = NewMergePatch(nil, &PatchClusterRequest{Name: "cluster"})
+	testErrNil(t, err)
+	if want, got := "cluster", patch["name"]; want != got {
+		t.Fatalf("want name: %s, got: %v", want, got)
+	}
+}
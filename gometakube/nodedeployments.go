@@ -19,6 +19,18 @@ func nodeDeploymentResourcePath(prj, dc, cls, id string) string {
 	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodedeployments/%s", prj, dc, cls, id)
 }
 
+func nodeDeploymentNodesPath(prj, dc, cls, id string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodedeployments/%s/nodes", prj, dc, cls, id)
+}
+
+func nodeDeploymentNodesEventsPath(prj, dc, cls, id string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodedeployments/%s/nodes/events", prj, dc, cls, id)
+}
+
+func nodeDeploymentNodesMetricsPath(prj, dc, cls, id string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodedeployments/%s/nodes/metrics", prj, dc, cls, id)
+}
+
 func clusterNodesUpgradePath(prj, dc, id string) string {
 	return fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodes/upgrades", prj, dc, id)
 }
@@ -44,6 +56,18 @@ func (svc *NodeDeploymentsService) Patch(ctx context.Context, prj, dc, cls, id s
 	return ret, resp, err
 }
 
+// MergePatch updates node deployment with merge patch turning from into to, fields missing in to are removed.
+func (svc *NodeDeploymentsService) MergePatch(ctx context.Context, prj, dc, cls, id string, from, to *NodeDeploymentsPatchRequest) (*NodeDeployment, *http.Response, error) {
+	patch, err := NewMergePatch(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := nodeDeploymentResourcePath(prj, dc, cls, id)
+	ret := new(NodeDeployment)
+	resp, err := svc.client.resourcePatch(ctx, path, patch, ret)
+	return ret, resp, err
+}
+
 // Create creates new node deployment.
 func (svc *NodeDeploymentsService) Create(ctx context.Context, prj, dc, cls string, v *NodeDeployment) (*NodeDeployment, *http.Response, error) {
 	path := nodeDeploymentsCreateListPath(prj, dc, cls)
@@ -76,3 +100,27 @@ func (svc *NodeDeploymentsService) Upgrade(ctx context.Context, prj, dc, cls str
 	path := clusterNodesUpgradePath(prj, dc, cls)
 	return svc.client.resourcePut(ctx, path, req, nil)
 }
+
+// Nodes returns nodes of node deployment.
+func (svc *NodeDeploymentsService) Nodes(ctx context.Context, prj, dc, cls, id string) ([]Node, *http.Response, error) {
+	path := nodeDeploymentNodesPath(prj, dc, cls, id)
+	ret := make([]Node, 0)
+	resp, err := svc.client.resourceList(ctx, path, &ret)
+	return ret, resp, err
+}
+
+// Events returns events of node deployment's nodes, all types are returned if eventType is empty.
+func (svc *NodeDeploymentsService) Events(ctx context.Context, prj, dc, cls, id, eventType string) ([]Event, *http.Response, error) {
+	path := eventsPath(nodeDeploymentNodesEventsPath(prj, dc, cls, id), eventType)
+	ret := make([]Event, 0)
+	resp, err := svc.client.resourceList(ctx, path, &ret)
+	return ret, resp, err
+}
+
+// Metrics returns resource usage of node deployment's nodes.
+func (svc *NodeDeploymentsService) Metrics(ctx context.Context, prj, dc, cls, id string) ([]NodeMetric, *http.Response, error) {
+	path := nodeDeploymentNodesMetricsPath(prj, dc, cls, id)
+	ret := make([]NodeMetric, 0)
+	resp, err := svc.client.resourceList(ctx, path, &ret)
+	return ret, resp, err
+}
@@ -1,11 +1,16 @@
 package gometakube
 
-import "time"
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
 
 type NodeDeployment struct {
 	ID                string                `json:"id,omitempty"`
 	Name              string                `json:"name"`
 	CreationTimestamp *time.Time            `json:"creationTimestamp,omitempty"`
+	Generation        uint                  `json:"generation,omitempty"`
 	Spec              NodeDeploymentSpec    `json:"spec"`
 	Status            *NodeDeploymentStatus `json:"status,omitempty"`
 }
@@ -15,14 +20,79 @@ type NodeDeploymentSpec struct {
 	MinReplicas uint                       `json:"minReplicas"`
 	MaxReplicas uint                       `json:"maxReplicas"`
 	Template    NodeDeploymentSpecTemplate `json:"template"`
-	Paused      bool                       `json:"paused,omitempty"`
+	Paused      bool                       `json:"paused"`
+	Strategy    *NodeDeploymentStrategy    `json:"strategy,omitempty"`
 }
 
+// NodeDeploymentStrategyTypeRollingUpdate replaces nodes gradually.
+const NodeDeploymentStrategyTypeRollingUpdate = "RollingUpdate"
+
+type NodeDeploymentStrategy struct {
+	Type          string                               `json:"type,omitempty"`
+	RollingUpdate *NodeDeploymentStrategyRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+type NodeDeploymentStrategyRollingUpdate struct {
+	MaxSurge       *IntOrString `json:"maxSurge,omitempty"`
+	MaxUnavailable *IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// IntOrString is a number of nodes, e.g. 1, or a percentage, e.g. 25%.
+// Numbers are encoded as json numbers, anything else as strings.
+type IntOrString string
+
+func (v IntOrString) MarshalJSON() ([]byte, error) {
+	if n, err := strconv.Atoi(string(v)); err == nil {
+		return []byte(strconv.Itoa(n)), nil
+	}
+	return json.Marshal(string(v))
+}
+
+func (v *IntOrString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = IntOrString(s)
+		return nil
+	}
+	var i int
+	if err := json.Unmarshal(data, &i); err != nil {
+		return err
+	}
+	*v = IntOrString(strconv.Itoa(i))
+	return nil
+}
+
+// NodeDeploymentSpecTemplate is a template of nodes.
+// Taints and Kubelet are sent when empty, so that patches remove them.
 type NodeDeploymentSpecTemplate struct {
 	Cloud           NodeDeploymentSpecTemplateCloud    `json:"cloud"`
 	OperatingSystem NodeDeploymentSpecTemplateOS       `json:"operatingSystem"`
 	Versions        NodeDeploymentSpecTemplateVersions `json:"versions,omitempty"`
 	Labels          map[string]string                  `json:"labels,omitempty"`
+	Taints          []TaintSpec                        `json:"taints"`
+	Kubelet         *NodeDeploymentSpecTemplateKubelet `json:"kubelet"`
+}
+
+type TaintSpec struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
+}
+
+// Taint effects.
+const (
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+	TaintEffectNoExecute        = "NoExecute"
+)
+
+// NodeDeploymentSpecTemplateKubelet is kubelet configuration of nodes.
+// Eviction maps are keyed by signal, e.g. memory.available.
+type NodeDeploymentSpecTemplateKubelet struct {
+	MaxPods                 *int              `json:"maxPods,omitempty"`
+	EvictionHard            map[string]string `json:"evictionHard,omitempty"`
+	EvictionSoft            map[string]string `json:"evictionSoft,omitempty"`
+	EvictionSoftGracePeriod map[string]string `json:"evictionSoftGracePeriod,omitempty"`
 }
 
 type NodeDeploymentSpecTemplateCloud struct {
@@ -30,17 +100,21 @@ type NodeDeploymentSpecTemplateCloud struct {
 }
 
 type NodeDeploymentSpecTemplateCloudOpenstack struct {
-	Flavor        string            `json:"flavor"`
-	Image         string            `json:"image"`
-	Tags          map[string]string `json:"tags"`
-	UseFloatingIP bool              `json:"useFloatingIP"`
-	// TODO(furkhat): DistSize (what are the fields).
+	Flavor           string            `json:"flavor"`
+	Image            string            `json:"image"`
+	Tags             map[string]string `json:"tags"`
+	UseFloatingIP    bool              `json:"useFloatingIP"`
+	AvailabilityZone string            `json:"availabilityZone,omitempty"`
+	// DiskSize is root volume size in GB, nodes boot from flavor's local disk if not set.
+	DiskSize           *int   `json:"diskSize,omitempty"`
+	RootDiskVolumeType string `json:"rootDiskVolumeType,omitempty"`
 }
 
 type NodeDeploymentSpecTemplateOS struct {
 	CentOS         *NodeDeploymentSpecTemplateOSOptions `json:"centos,omitempty"`
 	Ubuntu         *NodeDeploymentSpecTemplateOSOptions `json:"ubuntu,omitempty"`
 	ContainerLinux *NodeDeploymentSpecTemplateOSOptions `json:"containerLinux,omitempty"`
+	Flatcar        *NodeDeploymentSpecTemplateOSOptions `json:"flatcar,omitempty"`
 }
 
 type NodeDeploymentSpecTemplateOSOptions struct {
@@ -59,3 +133,49 @@ type NodeDeploymentStatus struct {
 	ReadyReplicas      uint `json:"readyReplicas"`
 	AvailableReplicas  uint `json:"availableReplicas"`
 }
+
+type Node struct {
+	ID                string      `json:"id,omitempty"`
+	Name              string      `json:"name"`
+	CreationTimestamp *time.Time  `json:"creationTimestamp,omitempty"`
+	DeletionTimestamp *time.Time  `json:"deletionTimestamp,omitempty"`
+	Status            *NodeStatus `json:"status,omitempty"`
+}
+
+type NodeStatus struct {
+	MachineName  string          `json:"machineName"`
+	Addresses    []NodeAddress   `json:"addresses"`
+	Allocatable  *NodeResources  `json:"allocatable,omitempty"`
+	Capacity     *NodeResources  `json:"capacity,omitempty"`
+	NodeInfo     *NodeSystemInfo `json:"nodeInfo,omitempty"`
+	ErrorReason  string          `json:"errorReason,omitempty"`
+	ErrorMessage string          `json:"errorMessage,omitempty"`
+}
+
+type NodeAddress struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+}
+
+type NodeResources struct {
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+}
+
+type NodeSystemInfo struct {
+	Architecture     string `json:"architecture"`
+	ContainerRuntime string `json:"containerRuntime"`
+	KernelVersion    string `json:"kernelVersion"`
+	KubeletVersion   string `json:"kubeletVersion"`
+	OperatingSystem  string `json:"operatingSystem"`
+}
+
+type NodeMetric struct {
+	Name                   string `json:"name"`
+	MemoryTotalBytes       int64  `json:"memoryTotalBytes"`
+	MemoryAvailableBytes   int64  `json:"memoryAvailableBytes"`
+	MemoryUsedPercentage   int64  `json:"memoryUsedPercentage"`
+	CPUTotalMillicores     int64  `json:"cpuTotalMillicores"`
+	CPUAvailableMillicores int64  `json:"cpuAvailableMillicores"`
+	CPUUsedPercentage      int64  `json:"cpuUsedPercentage"`
+}
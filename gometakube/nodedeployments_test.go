@@ -21,7 +21,9 @@ const (
 			"system-cluster": "j7f2svjll8",
 			"system-project": "5hrnkmpmp4"
 		  },
-		  "useFloatingIP": true
+		  "useFloatingIP": true,
+		  "availabilityZone": "dbl1",
+		  "diskSize": 50
 		}
 	  },
 	  "operatingSystem": {
@@ -35,15 +37,36 @@ const (
 	  "labels": {
 		"system/cluster": "j7f2svjll8",
 		"system/project": "5hrnkmpmp4"
+	  },
+	  "taints": [
+		{
+		  "key": "dedicated",
+		  "value": "ingress",
+		  "effect": "NoSchedule"
+		}
+	  ],
+	  "kubelet": {
+		"maxPods": 50,
+		"evictionHard": {
+		  "memory.available": "200Mi"
+		}
 	  }
 	},
-	"paused": false
+	"paused": false,
+	"strategy": {
+	  "type": "RollingUpdate",
+	  "rollingUpdate": {
+		"maxSurge": 1,
+		"maxUnavailable": "25%"
+	  }
+	}
   }`
 	nodeDeploymentJSON = `
   {
     "id": "metakube-worker-2xkvd",
     "name": "metakube-worker-2xkvd",
     "creationTimestamp": "2020-02-20T08:17:22Z",
+    "generation": 1,
     "spec": ` + nodeDeploymentSpecJSON + `,
     "status": {
       "observedGeneration": 1,
@@ -62,6 +85,7 @@ var nodeDeployment = NodeDeployment{
 	ID:                "metakube-worker-2xkvd",
 	Name:              "metakube-worker-2xkvd",
 	CreationTimestamp: testParseTime("2020-02-20T08:17:22Z"),
+	Generation:        1,
 	Spec: NodeDeploymentSpec{
 		Replicas: 3,
 		Template: NodeDeploymentSpecTemplate{
@@ -74,7 +98,9 @@ var nodeDeployment = NodeDeployment{
 						"system-cluster":   "j7f2svjll8",
 						"system-project":   "5hrnkmpmp4",
 					},
-					UseFloatingIP: true,
+					UseFloatingIP:    true,
+					AvailabilityZone: "dbl1",
+					DiskSize:         testIntPtr(50),
 				},
 			},
 			OperatingSystem: NodeDeploymentSpecTemplateOS{
@@ -89,8 +115,22 @@ var nodeDeployment = NodeDeployment{
 				"system/cluster": "j7f2svjll8",
 				"system/project": "5hrnkmpmp4",
 			},
+			Taints: []TaintSpec{
+				{Key: "dedicated", Value: "ingress", Effect: TaintEffectNoSchedule},
+			},
+			Kubelet: &NodeDeploymentSpecTemplateKubelet{
+				MaxPods:      testIntPtr(50),
+				EvictionHard: map[string]string{"memory.available": "200Mi"},
+			},
 		},
 		Paused: false,
+		Strategy: &NodeDeploymentStrategy{
+			Type: NodeDeploymentStrategyTypeRollingUpdate,
+			RollingUpdate: &NodeDeploymentStrategyRollingUpdate{
+				MaxSurge:       testIntOrString("1"),
+				MaxUnavailable: testIntOrString("25%"),
+			},
+		},
 	},
 	Status: &NodeDeploymentStatus{
 		ObservedGeneration: 1,
@@ -198,3 +238,107 @@ func TestNodeDeployments_Upgrade(t *testing.T) {
 		t.Fatalf("wanted upgrade request: %+v, got: %+v", want, got)
 	}
 }
+
+const nodeJSON = `
+  {
+	"id": "node-id",
+	"name": "metakube-worker-2xkvd-abc",
+	"creationTimestamp": "2020-02-20T08:20:00Z",
+	"status": {
+	  "machineName": "machine-metakube-worker-2xkvd-abc",
+	  "addresses": [{"address": "192.168.1.10", "type": "InternalIP"}],
+	  "nodeInfo": {"kubeletVersion": "v1.17.2"},
+	  "errorReason": "CreateMachineError",
+	  "errorMessage": "quota exceeded"
+	}
+  }`
+
+var node = Node{
+	ID:                "node-id",
+	Name:              "metakube-worker-2xkvd-abc",
+	CreationTimestamp: testParseTime("2020-02-20T08:20:00Z"),
+	Status: &NodeStatus{
+		MachineName:  "machine-metakube-worker-2xkvd-abc",
+		Addresses:    []NodeAddress{{Address: "192.168.1.10", Type: "InternalIP"}},
+		NodeInfo:     &NodeSystemInfo{KubeletVersion: "v1.17.2"},
+		ErrorReason:  "CreateMachineError",
+		ErrorMessage: "quota exceeded",
+	},
+}
+
+func TestNodeDeployments_Nodes(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodedeployments/%s/nodes", prj, dc, cls, nodeDeployment.ID)
+	want := []Node{node}
+	testResourceList(t, "["+nodeJSON+"]", path, want, func() (interface{}, error) {
+		l, _, err := client.NodeDeployments.Nodes(ctx, prj, dc, cls, nodeDeployment.ID)
+		return l, err
+	})
+}
+
+func TestNodeDeployments_Events(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodedeployments/%s/nodes/events", prj, dc, cls, nodeDeployment.ID)
+	want := []Event{event}
+	testResourceList(t, "["+eventJSON+"]", path, want, func() (interface{}, error) {
+		l, _, err := client.NodeDeployments.Events(ctx, prj, dc, cls, nodeDeployment.ID, EventTypeWarning)
+		return l, err
+	})
+}
+
+func TestNodeDeployments_Metrics(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/nodedeployments/%s/nodes/metrics", prj, dc, cls, nodeDeployment.ID)
+	metricsJSON := `[{
+		"name": "metakube-worker-2xkvd-abc",
+		"memoryTotalBytes": 2048,
+		"memoryAvailableBytes": 1024,
+		"memoryUsedPercentage": 50,
+		"cpuTotalMillicores": 1000,
+		"cpuAvailableMillicores": 900,
+		"cpuUsedPercentage": 10
+	}]`
+	want := []NodeMetric{{
+		Name:                   "metakube-worker-2xkvd-abc",
+		MemoryTotalBytes:       2048,
+		MemoryAvailableBytes:   1024,
+		MemoryUsedPercentage:   50,
+		CPUTotalMillicores:     1000,
+		CPUAvailableMillicores: 900,
+		CPUUsedPercentage:      10,
+	}}
+	testResourceList(t, metricsJSON, path, want, func() (interface{}, error) {
+		l, _, err := client.NodeDeployments.Metrics(ctx, prj, dc, cls, nodeDeployment.ID)
+		return l, err
+	})
+}
+
+func testIntOrString(v string) *IntOrString {
+	ret := IntOrString(v)
+	return &ret
+}
+
+func TestIntOrString_JSON(t *testing.T) {
+	for _, tc := range []struct {
+		v    IntOrString
+		json string
+	}{
+		{v: "1", json: `1`},
+		{v: "25%", json: `"25%"`},
+	} {
+		b, err := json.Marshal(tc.v)
+		testErrNil(t, err)
+		if want, got := tc.json, string(b); want != got {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+		var got IntOrString
+		testErrNil(t, json.Unmarshal(b, &got))
+		if tc.v != got {
+			t.Fatalf("want %s, got %s", tc.v, got)
+		}
+	}
+	for v, want := range map[IntOrString]string{"007": `7`, "+1": `1`} {
+		b, err := json.Marshal(v)
+		testErrNil(t, err)
+		if got := string(b); want != got {
+			t.Fatalf("%s: want %s, got %s", v, want, got)
+		}
+	}
+}
@@ -15,28 +15,43 @@ type OpenstackService struct {
 	client *Client
 }
 
+// OpenstackCredentials authenticate requests to user's openstack.
+// Either Username and Password or application credential must be set.
+type OpenstackCredentials struct {
+	Domain                      string
+	Username                    string
+	Password                    string
+	ApplicationCredentialID     string
+	ApplicationCredentialSecret string
+}
+
 // Images returns list of images.
-func (svc *OpenstackService) Images(ctx context.Context, dc, domain, username, password string) ([]Image, *http.Response, error) {
+func (svc *OpenstackService) Images(ctx context.Context, dc string, creds *OpenstackCredentials) ([]Image, *http.Response, error) {
 	ret := make([]Image, 0)
-	resp, err := svc.listResources(ctx, imagesListPath, dc, domain, username, password, &ret)
+	resp, err := svc.listResources(ctx, imagesListPath, dc, creds, &ret)
 	return ret, resp, err
 }
 
 // Tenants return list of tenants.
-func (svc *OpenstackService) Tenants(ctx context.Context, dc, domain, username, password string) ([]Tenant, *http.Response, error) {
+func (svc *OpenstackService) Tenants(ctx context.Context, dc string, creds *OpenstackCredentials) ([]Tenant, *http.Response, error) {
 	ret := make([]Tenant, 0)
-	resp, err := svc.listResources(ctx, tenantsListPath, dc, domain, username, password, &ret)
+	resp, err := svc.listResources(ctx, tenantsListPath, dc, creds, &ret)
 	return ret, resp, err
 }
 
-func (svc *OpenstackService) listResources(ctx context.Context, path, dc, domain, username, password string, ret interface{}) (*http.Response, error) {
+func (svc *OpenstackService) listResources(ctx context.Context, path, dc string, creds *OpenstackCredentials, ret interface{}) (*http.Response, error) {
 	req, err := svc.client.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("DatacenterName", dc)
-	req.Header.Set("Username", username)
-	req.Header.Set("Password", password)
-	req.Header.Set("Domain", domain)
+	req.Header.Set("Domain", creds.Domain)
+	if creds.ApplicationCredentialID != "" {
+		req.Header.Set("ApplicationCredentialID", creds.ApplicationCredentialID)
+		req.Header.Set("ApplicationCredentialSecret", creds.ApplicationCredentialSecret)
+	} else {
+		req.Header.Set("Username", creds.Username)
+		req.Header.Set("Password", creds.Password)
+	}
 	return svc.client.Do(ctx, req, &ret)
 }
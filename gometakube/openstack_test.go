@@ -75,7 +75,7 @@ func TestOpenstack_Images(t *testing.T) {
 	domain := "Default"
 	path := "/api/v1/providers/openstack/images"
 	testConfigureOpenstackHandleFunc(t, dcName, username, password, domain, path, imagesJSON)
-	got, _, err := client.Openstack.Images(ctx, dcName, domain, username, password)
+	got, _, err := client.Openstack.Images(ctx, dcName, &OpenstackCredentials{Domain: domain, Username: username, Password: password})
 	testErrNil(t, err)
 	if want := images; !reflect.DeepEqual(want, got) {
 		t.Fatalf("want: %+v, got: %+v", want, got)
@@ -93,7 +93,7 @@ func TestOpenstack_Tenants(t *testing.T) {
 	path := "/api/v1/providers/openstack/tenants"
 	testConfigureOpenstackHandleFunc(t, dcName, username, password, domain, path, tenantsJSON)
 
-	got, _, err := client.Openstack.Tenants(ctx, dcName, domain, username, password)
+	got, _, err := client.Openstack.Tenants(ctx, dcName, &OpenstackCredentials{Domain: domain, Username: username, Password: password})
 	testErrNil(t, err)
 	if want := tenants; !reflect.DeepEqual(want, got) {
 		t.Fatalf("want: %+v, got: %+v", want, got)
@@ -120,3 +120,33 @@ func testConfigureOpenstackHandleFunc(t *testing.T, dc, user, passw, domain, pat
 		fmt.Fprint(w, reply)
 	})
 }
+
+func TestOpenstack_ImagesApplicationCredential(t *testing.T) {
+	setup()
+	defer teardown()
+
+	path := "/api/v1/providers/openstack/images"
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if want, got := "appcredid", r.Header.Get("ApplicationCredentialID"); want != got {
+			t.Fatalf("want ApplicationCredentialID: %v, got: %v", want, got)
+		}
+		if want, got := "appcredsecret", r.Header.Get("ApplicationCredentialSecret"); want != got {
+			t.Fatalf("want ApplicationCredentialSecret: %v, got: %v", want, got)
+		}
+		if got := r.Header.Get("Username"); got != "" {
+			t.Fatalf("want no Username, got: %v", got)
+		}
+		fmt.Fprint(w, imagesJSON)
+	})
+	creds := &OpenstackCredentials{
+		Domain:                      "Default",
+		ApplicationCredentialID:     "appcredid",
+		ApplicationCredentialSecret: "appcredsecret",
+	}
+	got, _, err := client.Openstack.Images(ctx, "dc", creds)
+	testErrNil(t, err)
+	if want := images; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %+v, got: %+v", want, got)
+	}
+}
@@ -0,0 +1,37 @@
+package gometakube
+
+import (
+	"context"
+	"net/http"
+)
+
+type ifMatchKey struct{}
+
+// WithIfMatch returns context sending etag as If-Match precondition on PATCH and PUT requests,
+// so that a resource changed since etag was read is not overwritten.
+// Such request fails with an error for which IsConflict is true.
+func WithIfMatch(ctx context.Context, etag string) context.Context {
+	return context.WithValue(ctx, ifMatchKey{}, etag)
+}
+
+func ifMatch(ctx context.Context) string {
+	v, _ := ctx.Value(ifMatchKey{}).(string)
+	return v
+}
+
+// ETag returns version of a resource returned in response, empty if api did not return one.
+func ETag(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	return resp.Header.Get("ETag")
+}
+
+// IsConflict reports whether err is caused by a resource changed concurrently.
+func IsConflict(err error) bool {
+	v, ok := err.(*ErrorResponse)
+	if !ok || v.Response == nil {
+		return false
+	}
+	return v.Response.StatusCode == http.StatusConflict || v.Response.StatusCode == http.StatusPreconditionFailed
+}
@@ -0,0 +1,63 @@
+package gometakube
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProjects_UpdateIfMatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v1/projects/theprj", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		if want, got := `"v1"`, r.Header.Get("If-Match"); want != got {
+			t.Fatalf("want If-Match: %s, got: %s", want, got)
+		}
+		w.WriteHeader(http.StatusPreconditionFailed)
+	})
+	_, _, err := client.Projects.Update(WithIfMatch(ctx, `"v1"`), "theprj", &ProjectCreateAndUpdateRequest{Name: "edited"})
+	if !IsConflict(err) {
+		t.Fatalf("want conflict, got: %v", err)
+	}
+}
+
+func TestClient_resourcePatchRetriesOnConflict(t *testing.T) {
+	setup()
+	defer teardown()
+	client.retryOnConflictPeriod = time.Millisecond
+
+	calls := 0
+	mux.HandleFunc("/foo", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		if want, got := MergePatchContentType, r.Header.Get("Content-Type"); want != got {
+			t.Fatalf("want content type: %s, got: %s", want, got)
+		}
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		fmt.Fprint(w, `"bar"`)
+	})
+	var got string
+	_, err := client.resourcePatch(ctx, "/foo", MergePatch{"foo": "bar"}, &got)
+	testErrNil(t, err)
+	if want := "bar"; want != got {
+		t.Fatalf("want: %s, got: %s", want, got)
+	}
+	if want := 2; want != calls {
+		t.Fatalf("want calls: %d, got: %d", want, calls)
+	}
+
+	calls = 0
+	_, err = client.resourcePatch(WithIfMatch(ctx, `"v1"`), "/foo", MergePatch{"foo": "bar"}, &got)
+	if !IsConflict(err) {
+		t.Fatalf("want conditional patch not retried, got: %v", err)
+	}
+	if want := 1; want != calls {
+		t.Fatalf("want calls: %d, got: %d", want, calls)
+	}
+}
@@ -44,13 +44,10 @@ func (svc *ProjectsService) Get(ctx context.Context, id string) (*Project, *http
 }
 
 // Update updates a project.
+// Use WithIfMatch context to not overwrite concurrent changes.
 func (svc *ProjectsService) Update(ctx context.Context, id string, update *ProjectCreateAndUpdateRequest) (*Project, *http.Response, error) {
-	req, err := svc.client.NewRequest(http.MethodPut, projectResourcePath(id), update)
-	if err != nil {
-		return nil, nil, err
-	}
 	ret := new(Project)
-	resp, err := svc.client.Do(ctx, req, &ret)
+	resp, err := svc.client.resourcePut(ctx, projectResourcePath(id), update, ret)
 	return ret, resp, err
 }
 
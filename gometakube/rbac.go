@@ -0,0 +1,96 @@
+package gometakube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RBACService handles communication with cluster roles and bindings related endpoints.
+type RBACService struct {
+	client *Client
+}
+
+func clusterRoleNamesPath(prj, dc, cls string) string {
+	return clusterResourcePath(prj, dc, cls) + "/clusterrolenames"
+}
+
+func clusterBindingsPath(prj, dc, cls string) string {
+	return clusterResourcePath(prj, dc, cls) + "/clusterbindings"
+}
+
+func clusterRoleBindingsPath(prj, dc, cls, role string) string {
+	return fmt.Sprintf("%s/clusterroles/%s/clusterbindings", clusterResourcePath(prj, dc, cls), role)
+}
+
+func roleNamesPath(prj, dc, cls string) string {
+	return clusterResourcePath(prj, dc, cls) + "/rolenames"
+}
+
+func roleBindingsListPath(prj, dc, cls string) string {
+	return clusterResourcePath(prj, dc, cls) + "/bindings"
+}
+
+func roleBindingsPath(prj, dc, cls, namespace, role string) string {
+	return fmt.Sprintf("%s/roles/%s/%s/bindings", clusterResourcePath(prj, dc, cls), namespace, role)
+}
+
+// ClusterRoleNames returns names of cluster roles managed by MetaKube.
+func (svc *RBACService) ClusterRoleNames(ctx context.Context, prj, dc, cls string) ([]ClusterRoleName, *http.Response, error) {
+	ret := make([]ClusterRoleName, 0)
+	resp, err := svc.client.resourceList(ctx, clusterRoleNamesPath(prj, dc, cls), &ret)
+	return ret, resp, err
+}
+
+// ClusterBindings returns list of cluster role bindings in a cluster.
+func (svc *RBACService) ClusterBindings(ctx context.Context, prj, dc, cls string) ([]ClusterRoleBinding, *http.Response, error) {
+	ret := make([]ClusterRoleBinding, 0)
+	resp, err := svc.client.resourceList(ctx, clusterBindingsPath(prj, dc, cls), &ret)
+	return ret, resp, err
+}
+
+// BindClusterRole binds user or group to a cluster role.
+func (svc *RBACService) BindClusterRole(ctx context.Context, prj, dc, cls, role string, subject *ClusterRoleUser) (*ClusterRoleBinding, *http.Response, error) {
+	ret := new(ClusterRoleBinding)
+	resp, err := svc.client.resourceCreate(ctx, clusterRoleBindingsPath(prj, dc, cls, role), subject, ret)
+	return ret, resp, err
+}
+
+// UnbindClusterRole removes user or group from a cluster role binding.
+func (svc *RBACService) UnbindClusterRole(ctx context.Context, prj, dc, cls, role string, subject *ClusterRoleUser) (*http.Response, error) {
+	req, err := svc.client.NewRequest(http.MethodDelete, clusterRoleBindingsPath(prj, dc, cls, role), subject)
+	if err != nil {
+		return nil, err
+	}
+	return svc.client.Do(ctx, req, nil)
+}
+
+// RoleNames returns names of namespaced roles managed by MetaKube.
+func (svc *RBACService) RoleNames(ctx context.Context, prj, dc, cls string) ([]RoleName, *http.Response, error) {
+	ret := make([]RoleName, 0)
+	resp, err := svc.client.resourceList(ctx, roleNamesPath(prj, dc, cls), &ret)
+	return ret, resp, err
+}
+
+// RoleBindings returns list of role bindings in all namespaces of a cluster.
+func (svc *RBACService) RoleBindings(ctx context.Context, prj, dc, cls string) ([]RoleBinding, *http.Response, error) {
+	ret := make([]RoleBinding, 0)
+	resp, err := svc.client.resourceList(ctx, roleBindingsListPath(prj, dc, cls), &ret)
+	return ret, resp, err
+}
+
+// BindRole binds user or group to a role in a namespace.
+func (svc *RBACService) BindRole(ctx context.Context, prj, dc, cls, namespace, role string, subject *RoleUser) (*RoleBinding, *http.Response, error) {
+	ret := new(RoleBinding)
+	resp, err := svc.client.resourceCreate(ctx, roleBindingsPath(prj, dc, cls, namespace, role), subject, ret)
+	return ret, resp, err
+}
+
+// UnbindRole removes user or group from a role binding in a namespace.
+func (svc *RBACService) UnbindRole(ctx context.Context, prj, dc, cls, namespace, role string, subject *RoleUser) (*http.Response, error) {
+	req, err := svc.client.NewRequest(http.MethodDelete, roleBindingsPath(prj, dc, cls, namespace, role), subject)
+	if err != nil {
+		return nil, err
+	}
+	return svc.client.Do(ctx, req, nil)
+}
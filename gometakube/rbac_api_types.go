@@ -0,0 +1,38 @@
+package gometakube
+
+type ClusterRoleName struct {
+	Name string `json:"name"`
+}
+
+type RoleName struct {
+	Name      string   `json:"name"`
+	Namespace []string `json:"namespace"`
+}
+
+type ClusterRoleUser struct {
+	UserEmail string `json:"userEmail,omitempty"`
+	Group     string `json:"group,omitempty"`
+}
+
+type RoleUser struct {
+	UserEmail string `json:"userEmail,omitempty"`
+	Group     string `json:"group,omitempty"`
+}
+
+type ClusterRoleBinding struct {
+	RoleRefName string        `json:"roleRefName"`
+	Subjects    []RBACSubject `json:"subjects"`
+}
+
+type RoleBinding struct {
+	Namespace   string        `json:"namespace"`
+	RoleRefName string        `json:"roleRefName"`
+	Subjects    []RBACSubject `json:"subjects"`
+}
+
+type RBACSubject struct {
+	APIGroup  string `json:"apiGroup,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
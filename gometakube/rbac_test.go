@@ -0,0 +1,153 @@
+package gometakube
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	clusterRoleBindingJSON = `{
+  "roleRefName": "admin",
+  "subjects": [
+	{
+	  "apiGroup": "rbac.authorization.k8s.io",
+	  "kind": "Group",
+	  "name": "oidc:admins"
+	}
+  ]
+}`
+	roleBindingJSON = `{
+  "namespace": "default",
+  "roleRefName": "edit",
+  "subjects": [
+	{
+	  "apiGroup": "rbac.authorization.k8s.io",
+	  "kind": "User",
+	  "name": "dev@example.com"
+	}
+  ]
+}`
+)
+
+var (
+	clusterRoleBinding = ClusterRoleBinding{
+		RoleRefName: "admin",
+		Subjects: []RBACSubject{
+			{APIGroup: "rbac.authorization.k8s.io", Kind: "Group", Name: "oidc:admins"},
+		},
+	}
+	roleBinding = RoleBinding{
+		Namespace:   "default",
+		RoleRefName: "edit",
+		Subjects: []RBACSubject{
+			{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: "dev@example.com"},
+		},
+	}
+)
+
+func TestRBAC_ClusterRoleNames(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/clusterrolenames", prj, dc, cls)
+	want := []ClusterRoleName{{Name: "admin"}, {Name: "view"}}
+	testResourceList(t, `[{"name": "admin"}, {"name": "view"}]`, path, want, func() (interface{}, error) {
+		l, _, err := client.RBAC.ClusterRoleNames(ctx, prj, dc, cls)
+		return l, err
+	})
+}
+
+func TestRBAC_ClusterBindings(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/clusterbindings", prj, dc, cls)
+	want := []ClusterRoleBinding{clusterRoleBinding}
+	testResourceList(t, "["+clusterRoleBindingJSON+"]", path, want, func() (interface{}, error) {
+		l, _, err := client.RBAC.ClusterBindings(ctx, prj, dc, cls)
+		return l, err
+	})
+}
+
+func TestRBAC_BindClusterRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	subject := &ClusterRoleUser{Group: "oidc:admins"}
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/clusterroles/admin/clusterbindings", prj, dc, cls)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		v := new(ClusterRoleUser)
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, subject) {
+			t.Fatalf("want: %+v, got: %+v", subject, v)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, clusterRoleBindingJSON)
+	})
+
+	got, _, err := client.RBAC.BindClusterRole(ctx, prj, dc, cls, "admin", subject)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &clusterRoleBinding) {
+		t.Fatalf("want: %+v, got: %+v", clusterRoleBinding, got)
+	}
+}
+
+func TestRBAC_UnbindClusterRole(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/clusterroles/admin/clusterbindings", prj, dc, cls)
+	testResourceDelete(t, path, func() error {
+		_, err := client.RBAC.UnbindClusterRole(ctx, prj, dc, cls, "admin", &ClusterRoleUser{Group: "oidc:admins"})
+		return err
+	})
+}
+
+func TestRBAC_RoleNames(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/rolenames", prj, dc, cls)
+	want := []RoleName{{Name: "edit", Namespace: []string{"default", "kube-system"}}}
+	testResourceList(t, `[{"name": "edit", "namespace": ["default", "kube-system"]}]`, path, want, func() (interface{}, error) {
+		l, _, err := client.RBAC.RoleNames(ctx, prj, dc, cls)
+		return l, err
+	})
+}
+
+func TestRBAC_RoleBindings(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/bindings", prj, dc, cls)
+	want := []RoleBinding{roleBinding}
+	testResourceList(t, "["+roleBindingJSON+"]", path, want, func() (interface{}, error) {
+		l, _, err := client.RBAC.RoleBindings(ctx, prj, dc, cls)
+		return l, err
+	})
+}
+
+func TestRBAC_BindRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	subject := &RoleUser{UserEmail: "dev@example.com"}
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/roles/default/edit/bindings", prj, dc, cls)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		v := new(RoleUser)
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, subject) {
+			t.Fatalf("want: %+v, got: %+v", subject, v)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, roleBindingJSON)
+	})
+
+	got, _, err := client.RBAC.BindRole(ctx, prj, dc, cls, "default", "edit", subject)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &roleBinding) {
+		t.Fatalf("want: %+v, got: %+v", roleBinding, got)
+	}
+}
+
+func TestRBAC_UnbindRole(t *testing.T) {
+	path := fmt.Sprintf("/api/v1/projects/%s/dc/%s/clusters/%s/roles/default/edit/bindings", prj, dc, cls)
+	testResourceDelete(t, path, func() error {
+		_, err := client.RBAC.UnbindRole(ctx, prj, dc, cls, "default", "edit", &RoleUser{UserEmail: "dev@example.com"})
+		return err
+	})
+}
@@ -0,0 +1,94 @@
+package gometakube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ServiceAccountsService handles communication with service accounts and tokens related endpoints.
+type ServiceAccountsService struct {
+	client *Client
+}
+
+func serviceAccountsPath(prj string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/serviceaccounts", prj)
+}
+
+func serviceAccountResourcePath(prj, id string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s", prj, id)
+}
+
+func serviceAccountTokensPath(prj, sa string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s/tokens", prj, sa)
+}
+
+func serviceAccountTokenResourcePath(prj, sa, id string) string {
+	return fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s/tokens/%s", prj, sa, id)
+}
+
+// List returns list of service accounts in a project.
+func (svc *ServiceAccountsService) List(ctx context.Context, prj string) ([]ServiceAccount, *http.Response, error) {
+	ret := make([]ServiceAccount, 0)
+	resp, err := svc.client.resourceList(ctx, serviceAccountsPath(prj), &ret)
+	return ret, resp, err
+}
+
+// Create creates a service account in a project.
+func (svc *ServiceAccountsService) Create(ctx context.Context, prj string, create *ServiceAccount) (*ServiceAccount, *http.Response, error) {
+	ret := new(ServiceAccount)
+	resp, err := svc.client.resourceCreate(ctx, serviceAccountsPath(prj), create, ret)
+	return ret, resp, err
+}
+
+// Update updates service account's name and group.
+func (svc *ServiceAccountsService) Update(ctx context.Context, prj, id string, update *ServiceAccount) (*ServiceAccount, *http.Response, error) {
+	ret := new(ServiceAccount)
+	resp, err := svc.client.resourcePut(ctx, serviceAccountResourcePath(prj, id), update, ret)
+	return ret, resp, err
+}
+
+// Delete deletes a service account from a project.
+func (svc *ServiceAccountsService) Delete(ctx context.Context, prj, id string) (*http.Response, error) {
+	return svc.client.resourceDelete(ctx, serviceAccountResourcePath(prj, id))
+}
+
+// ListTokens returns list of service account's tokens.
+// Secret token values are not included.
+func (svc *ServiceAccountsService) ListTokens(ctx context.Context, prj, sa string) ([]ServiceAccountToken, *http.Response, error) {
+	ret := make([]ServiceAccountToken, 0)
+	resp, err := svc.client.resourceList(ctx, serviceAccountTokensPath(prj, sa), &ret)
+	return ret, resp, err
+}
+
+// CreateToken creates a service account token.
+// Returned token is the only place to read the secret value from.
+func (svc *ServiceAccountsService) CreateToken(ctx context.Context, prj, sa string, create *ServiceAccountToken) (*ServiceAccountToken, *http.Response, error) {
+	ret := new(ServiceAccountToken)
+	resp, err := svc.client.resourceCreate(ctx, serviceAccountTokensPath(prj, sa), create, ret)
+	return ret, resp, err
+}
+
+// PatchTokenRequest specifies fields to be changed on a token.
+type PatchTokenRequest struct {
+	Name string `json:"name"`
+}
+
+// PatchToken changes token's name, secret value stays the same.
+func (svc *ServiceAccountsService) PatchToken(ctx context.Context, prj, sa, id string, patch *PatchTokenRequest) (*ServiceAccountToken, *http.Response, error) {
+	ret := new(ServiceAccountToken)
+	resp, err := svc.client.resourcePatch(ctx, serviceAccountTokenResourcePath(prj, sa, id), patch, ret)
+	return ret, resp, err
+}
+
+// RotateToken regenerates token's secret value, old value is invalidated.
+func (svc *ServiceAccountsService) RotateToken(ctx context.Context, prj, sa string, token *ServiceAccountToken) (*ServiceAccountToken, *http.Response, error) {
+	ret := new(ServiceAccountToken)
+	resp, err := svc.client.resourcePut(ctx, serviceAccountTokenResourcePath(prj, sa, token.ID), token, ret)
+	return ret, resp, err
+}
+
+// DeleteToken deletes a service account token.
+func (svc *ServiceAccountsService) DeleteToken(ctx context.Context, prj, sa, id string) (*http.Response, error) {
+	return svc.client.resourceDelete(ctx, serviceAccountTokenResourcePath(prj, sa, id))
+}
@@ -0,0 +1,22 @@
+package gometakube
+
+import "time"
+
+type ServiceAccount struct {
+	CreationTimestamp *time.Time `json:"creationTimestamp,omitempty"`
+	DeletionTimestamp *time.Time `json:"deletionTimestamp,omitempty"`
+	ID                string     `json:"id,omitempty"`
+	Name              string     `json:"name"`
+	Group             string     `json:"group"`
+	Status            string     `json:"status,omitempty"`
+}
+
+type ServiceAccountToken struct {
+	CreationTimestamp *time.Time `json:"creationTimestamp,omitempty"`
+	DeletionTimestamp *time.Time `json:"deletionTimestamp,omitempty"`
+	Expiry            *time.Time `json:"expiry,omitempty"`
+	ID                string     `json:"id,omitempty"`
+	Name              string     `json:"name"`
+	// Token is a secret value, api returns it only on create and rotate.
+	Token string `json:"token,omitempty"`
+}
@@ -0,0 +1,163 @@
+package gometakube
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	serviceAccountJSON = `{
+  "creationTimestamp": "2020-04-01T10:00:00Z",
+  "id": "sa-id",
+  "name": "ci",
+  "group": "editors-theproject",
+  "status": "Active"
+}`
+	serviceAccountTokenJSON = `{
+  "creationTimestamp": "2020-04-01T10:00:00Z",
+  "expiry": "2023-04-01T10:00:00Z",
+  "id": "sa-token-id",
+  "name": "pipeline",
+  "token": "secret"
+}`
+)
+
+var (
+	serviceAccount = ServiceAccount{
+		CreationTimestamp: testParseTime("2020-04-01T10:00:00Z"),
+		ID:                "sa-id",
+		Name:              "ci",
+		Group:             "editors-theproject",
+		Status:            "Active",
+	}
+	serviceAccountToken = ServiceAccountToken{
+		CreationTimestamp: testParseTime("2020-04-01T10:00:00Z"),
+		Expiry:            testParseTime("2023-04-01T10:00:00Z"),
+		ID:                "sa-token-id",
+		Name:              "pipeline",
+		Token:             "secret",
+	}
+)
+
+func TestServiceAccounts_List(t *testing.T) {
+	prj := "theproject"
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts", prj)
+	want := []ServiceAccount{serviceAccount}
+	testResourceList(t, "["+serviceAccountJSON+"]", path, want, func() (interface{}, error) {
+		l, _, err := client.ServiceAccounts.List(ctx, prj)
+		return l, err
+	})
+}
+
+func TestServiceAccounts_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	prj := "theproject"
+	create := &ServiceAccount{Name: "ci", Group: "editors"}
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts", prj)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		v := new(ServiceAccount)
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, create) {
+			t.Fatalf("want: %+v, got: %+v", create, v)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, serviceAccountJSON)
+	})
+
+	got, _, err := client.ServiceAccounts.Create(ctx, prj, create)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &serviceAccount) {
+		t.Fatalf("want: %+v, got: %+v", serviceAccount, got)
+	}
+}
+
+func TestServiceAccounts_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	prj := "theproject"
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s", prj, serviceAccount.ID)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		fmt.Fprint(w, serviceAccountJSON)
+	})
+
+	got, _, err := client.ServiceAccounts.Update(ctx, prj, serviceAccount.ID, &serviceAccount)
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &serviceAccount) {
+		t.Fatalf("want: %+v, got: %+v", serviceAccount, got)
+	}
+}
+
+func TestServiceAccounts_Delete(t *testing.T) {
+	prj := "theproject"
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s", prj, serviceAccount.ID)
+	testResourceDelete(t, path, func() error {
+		_, err := client.ServiceAccounts.Delete(ctx, prj, serviceAccount.ID)
+		return err
+	})
+}
+
+func TestServiceAccounts_ListTokens(t *testing.T) {
+	prj := "theproject"
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s/tokens", prj, serviceAccount.ID)
+	want := []ServiceAccountToken{serviceAccountToken}
+	testResourceList(t, "["+serviceAccountTokenJSON+"]", path, want, func() (interface{}, error) {
+		l, _, err := client.ServiceAccounts.ListTokens(ctx, prj, serviceAccount.ID)
+		return l, err
+	})
+}
+
+func TestServiceAccounts_CreateToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	prj := "theproject"
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s/tokens", prj, serviceAccount.ID)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, serviceAccountTokenJSON)
+	})
+
+	got, _, err := client.ServiceAccounts.CreateToken(ctx, prj, serviceAccount.ID, &ServiceAccountToken{Name: "pipeline"})
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &serviceAccountToken) {
+		t.Fatalf("want: %+v, got: %+v", serviceAccountToken, got)
+	}
+}
+
+func TestServiceAccounts_RotateToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	prj := "theproject"
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s/tokens/%s", prj, serviceAccount.ID, serviceAccountToken.ID)
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		fmt.Fprint(w, serviceAccountTokenJSON)
+	})
+
+	got, _, err := client.ServiceAccounts.RotateToken(ctx, prj, serviceAccount.ID, &ServiceAccountToken{ID: serviceAccountToken.ID, Name: "pipeline"})
+	testErrNil(t, err)
+	if !reflect.DeepEqual(got, &serviceAccountToken) {
+		t.Fatalf("want: %+v, got: %+v", serviceAccountToken, got)
+	}
+}
+
+func TestServiceAccounts_DeleteToken(t *testing.T) {
+	prj := "theproject"
+	path := fmt.Sprintf("/api/v1/projects/%s/serviceaccounts/%s/tokens/%s", prj, serviceAccount.ID, serviceAccountToken.ID)
+	testResourceDelete(t, path, func() error {
+		_, err := client.ServiceAccounts.DeleteToken(ctx, prj, serviceAccount.ID, serviceAccountToken.ID)
+		return err
+	})
+}
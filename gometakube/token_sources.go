@@ -0,0 +1,147 @@
+package gometakube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// DefaultTokenURL is a token endpoint of MetaKube identity provider.
+	DefaultTokenURL = defaultBaseURL + "/dex/token"
+)
+
+// WithTokenSource used for api client authenticated with tokens from ts.
+func WithTokenSource(ts oauth2.TokenSource) CreateOpt {
+	return func() *http.Client {
+		return oauth2.NewClient(context.Background(), ts)
+	}
+}
+
+// RefreshTokenConfig configures OAuth2 refresh token flow.
+type RefreshTokenConfig struct {
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+	// TokenURL defaults to DefaultTokenURL.
+	TokenURL string
+}
+
+// NewRefreshTokenSource returns token source exchanging refresh token for short lived access tokens.
+// Access token is renewed when it expires.
+func NewRefreshTokenSource(cfg RefreshTokenConfig) oauth2.TokenSource {
+	tokenURL := cfg.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	conf := &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: tokenURL},
+	}
+	return conf.TokenSource(context.Background(), &oauth2.Token{RefreshToken: cfg.RefreshToken})
+}
+
+// NewFileTokenSource returns token source reading access token from a file.
+// The file is read again whenever its modification time changes,
+// so tokens renewed by external tooling are picked up.
+func NewFileTokenSource(path string) oauth2.TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+type fileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	token   *oauth2.Token
+}
+
+func (s *fileTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("token file: %v", err)
+	}
+	if s.token != nil && info.ModTime().Equal(s.modTime) {
+		return s.token, nil
+	}
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("read token file: %v", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil, fmt.Errorf("token file %s is empty", s.path)
+	}
+	s.token = &oauth2.Token{AccessToken: token}
+	s.modTime = info.ModTime()
+	return s.token, nil
+}
+
+// ExecConfig configures external credential helper command.
+type ExecConfig struct {
+	Command string
+	Args    []string
+	// Env is added to the environment of the provider process.
+	Env map[string]string
+}
+
+// NewExecTokenSource returns token source running a credential helper command.
+// The command must print ExecCredential json, the same kubectl exec plugins print:
+//
+//	{"kind": "ExecCredential", "status": {"token": "...", "expirationTimestamp": "..."}}
+//
+// The command is run again when the token expires, tokens without expiration are cached forever.
+func NewExecTokenSource(cfg ExecConfig) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &execTokenSource{cfg: cfg})
+}
+
+type execTokenSource struct {
+	cfg ExecConfig
+}
+
+type execCredential struct {
+	Kind   string `json:"kind"`
+	Status *struct {
+		Token               string     `json:"token"`
+		ExpirationTimestamp *time.Time `json:"expirationTimestamp"`
+	} `json:"status"`
+}
+
+func (s *execTokenSource) Token() (*oauth2.Token, error) {
+	cmd := exec.Command(s.cfg.Command, s.cfg.Args...)
+	cmd.Env = os.Environ()
+	for k, v := range s.cfg.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("run credential helper `%s`: %v: %s", s.cfg.Command, err, strings.TrimSpace(stderr.String()))
+	}
+	cred := new(execCredential)
+	if err := json.Unmarshal(out, cred); err != nil {
+		return nil, fmt.Errorf("parse credential helper `%s` output: %v", s.cfg.Command, err)
+	}
+	if cred.Kind != "ExecCredential" || cred.Status == nil || cred.Status.Token == "" {
+		return nil, fmt.Errorf("credential helper `%s` returned no token", s.cfg.Command)
+	}
+	ret := &oauth2.Token{AccessToken: cred.Status.Token}
+	if cred.Status.ExpirationTimestamp != nil {
+		ret.Expiry = *cred.Status.ExpirationTimestamp
+	}
+	return ret, nil
+}
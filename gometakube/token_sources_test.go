@@ -0,0 +1,79 @@
+package gometakube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTokenSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "token")
+	testErrNil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+	testErrNil(t, ioutil.WriteFile(path, []byte("first\n"), 0600))
+
+	ts := NewFileTokenSource(path)
+	token, err := ts.Token()
+	testErrNil(t, err)
+	if want, got := "first", token.AccessToken; want != got {
+		t.Fatalf("want token: %s, got: %s", want, got)
+	}
+
+	testErrNil(t, ioutil.WriteFile(path, []byte("second"), 0600))
+	later := time.Now().Add(time.Minute)
+	testErrNil(t, os.Chtimes(path, later, later))
+	token, err = ts.Token()
+	testErrNil(t, err)
+	if want, got := "second", token.AccessToken; want != got {
+		t.Fatalf("want token re-read: %s, got: %s", want, got)
+	}
+}
+
+func TestExecTokenSource(t *testing.T) {
+	ts := NewExecTokenSource(ExecConfig{
+		Command: "sh",
+		Args:    []string{"-c", `echo "{\"kind\": \"ExecCredential\", \"status\": {\"token\": \"$TOKEN\"}}"`},
+		Env:     map[string]string{"TOKEN": "fromhelper"},
+	})
+	token, err := ts.Token()
+	testErrNil(t, err)
+	if want, got := "fromhelper", token.AccessToken; want != got {
+		t.Fatalf("want token: %s, got: %s", want, got)
+	}
+
+	ts = NewExecTokenSource(ExecConfig{Command: "sh", Args: []string{"-c", "echo not json"}})
+	if _, err := ts.Token(); err == nil {
+		t.Fatal("want error on invalid helper output")
+	}
+}
+
+func TestRefreshTokenSource(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/dex/token", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		testErrNil(t, r.ParseForm())
+		if want, got := "therefreshtoken", r.PostForm.Get("refresh_token"); want != got {
+			t.Fatalf("want refresh_token: %s, got: %s", want, got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "theaccesstoken", "token_type": "bearer", "expires_in": 300}`)
+	})
+
+	ts := NewRefreshTokenSource(RefreshTokenConfig{
+		RefreshToken: "therefreshtoken",
+		ClientID:     "metakube",
+		TokenURL:     server.URL + "/dex/token",
+	})
+	token, err := ts.Token()
+	testErrNil(t, err)
+	if want, got := "theaccesstoken", token.AccessToken; want != got {
+		t.Fatalf("want token: %s, got: %s", want, got)
+	}
+}
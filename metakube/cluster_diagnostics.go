@@ -0,0 +1,106 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+// Number of the latest warning events to include into error messages.
+const diagnosticsEventsLimit = 5
+
+// Time to get diagnostics after operation timed out.
+const diagnosticsTimeout = 30 * time.Second
+
+// clusterWaitError reports wait stopped by ctx. Diagnostics are appended on timeout only,
+// when terraform is interrupted they would just delay the exit.
+func clusterWaitError(ctx context.Context, client *gometakube.Client, prj, dc, cls, msg string) error {
+	if ctx.Err() != context.DeadlineExceeded {
+		return errors.Wrap(ctx.Err(), msg)
+	}
+	diagnosticsCtx, cancel := context.WithTimeout(context.Background(), diagnosticsTimeout)
+	defer cancel()
+	return errors.Errorf("%s: timeout%s", msg, clusterDiagnostics(diagnosticsCtx, client, prj, dc, cls))
+}
+
+// clusterDiagnostics describes latest warning events of a cluster and status of its nodes.
+// It is meant to be appended to wait errors, so failures to get details are reported inline.
+func clusterDiagnostics(ctx context.Context, client *gometakube.Client, prj, dc, cls string) string {
+	var b strings.Builder
+	events, _, err := client.Clusters.Events(ctx, prj, dc, cls, gometakube.EventTypeWarning)
+	if err != nil {
+		fmt.Fprintf(&b, "\ncould not list cluster events: %v", err)
+	} else {
+		writeEvents(&b, "cluster warning events", events)
+	}
+	nodedepls, _, err := client.NodeDeployments.List(ctx, prj, dc, cls)
+	if err != nil {
+		fmt.Fprintf(&b, "\ncould not list node deployments: %v", err)
+		return b.String()
+	}
+	for _, nodedepl := range nodedepls {
+		b.WriteString(nodeDeploymentDiagnostics(ctx, client, prj, dc, cls, &nodedepl))
+	}
+	return b.String()
+}
+
+// nodeDeploymentDiagnostics describes status of node deployment's nodes and their latest warning events.
+func nodeDeploymentDiagnostics(ctx context.Context, client *gometakube.Client, prj, dc, cls string, nodedepl *gometakube.NodeDeployment) string {
+	var b strings.Builder
+	nodes, _, err := client.NodeDeployments.Nodes(ctx, prj, dc, cls, nodedepl.ID)
+	if err != nil {
+		fmt.Fprintf(&b, "\ncould not list nodes of node deployment `%s`: %v", nodedepl.Name, err)
+	} else {
+		fmt.Fprintf(&b, "\nnode deployment `%s` nodes:", nodedepl.Name)
+		if len(nodes) == 0 {
+			b.WriteString("\n  * none")
+		}
+		for _, node := range nodes {
+			fmt.Fprintf(&b, "\n  * %s", nodeStatusDescription(&node))
+		}
+	}
+	events, _, err := client.NodeDeployments.Events(ctx, prj, dc, cls, nodedepl.ID, gometakube.EventTypeWarning)
+	if err != nil {
+		fmt.Fprintf(&b, "\ncould not list node deployment `%s` events: %v", nodedepl.Name, err)
+	} else {
+		writeEvents(&b, fmt.Sprintf("node deployment `%s` warning events", nodedepl.Name), events)
+	}
+	return b.String()
+}
+
+func nodeStatusDescription(node *gometakube.Node) string {
+	if node.Status == nil {
+		return node.Name + ": no status"
+	}
+	status := "running"
+	if node.DeletionTimestamp != nil {
+		status = "deleting"
+	} else if node.Status.ErrorReason != "" {
+		status = fmt.Sprintf("%s: %s", node.Status.ErrorReason, node.Status.ErrorMessage)
+	} else if node.Status.NodeInfo == nil || node.Status.NodeInfo.KubeletVersion == "" {
+		status = "provisioning"
+	}
+	return fmt.Sprintf("%s (machine %s): %s", node.Name, node.Status.MachineName, status)
+}
+
+func writeEvents(b *strings.Builder, title string, events []gometakube.Event) {
+	if len(events) == 0 {
+		return
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		ti, tj := events[i].LastTimestamp, events[j].LastTimestamp
+		return ti != nil && (tj == nil || ti.After(*tj))
+	})
+	if len(events) > diagnosticsEventsLimit {
+		events = events[:diagnosticsEventsLimit]
+	}
+	fmt.Fprintf(b, "\n%s:", title)
+	for _, e := range events {
+		fmt.Fprintf(b, "\n  * %s %s: %s (x%d)", e.InvolvedObject.Type, e.InvolvedObject.Name, e.Message, e.Count)
+	}
+}
@@ -0,0 +1,300 @@
+package metakube
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+// Node deployment operating systems.
+const (
+	nodeOSUbuntu         = "ubuntu"
+	nodeOSCentOS         = "centos"
+	nodeOSContainerLinux = "container_linux"
+	nodeOSFlatcar        = "flatcar"
+)
+
+// nodeSystemLabelPrefix is a prefix of node labels MetaKube sets itself.
+const nodeSystemLabelPrefix = "system/"
+
+// nodeSystemTagKey is a tag MetaKube sets on node instances itself,
+// next to tags with nodeSystemTagPrefix.
+const (
+	nodeSystemTagKey    = "metakube-cluster"
+	nodeSystemTagPrefix = "system-"
+)
+
+var nodeOSNames = []string{nodeOSUbuntu, nodeOSCentOS, nodeOSContainerLinux, nodeOSFlatcar}
+
+// nodeOSImageDistros are values of image os_distro metadata each operating system runs on.
+var nodeOSImageDistros = map[string][]string{
+	nodeOSUbuntu:         {"ubuntu"},
+	nodeOSCentOS:         {"centos"},
+	nodeOSContainerLinux: {"coreos", "container-linux"},
+	nodeOSFlatcar:        {"flatcar"},
+}
+
+// nodeDeploymentOS returns operating system spec of nodedepl, Ubuntu without dist upgrade if not configured.
+func nodeDeploymentOS(nodedepl map[string]interface{}) gometakube.NodeDeploymentSpecTemplateOS {
+	name, distUpgradeOnBoot, disableAutoUpdate := nodeOSUbuntu, false, false
+	if l, ok := nodedepl["operating_system"].([]interface{}); ok && len(l) == 1 && l[0] != nil {
+		v := l[0].(map[string]interface{})
+		name = v["name"].(string)
+		distUpgradeOnBoot = v["dist_upgrade_on_boot"].(bool)
+		disableAutoUpdate = v["disable_auto_update"].(bool)
+	}
+	var ret gometakube.NodeDeploymentSpecTemplateOS
+	switch name {
+	case nodeOSCentOS:
+		ret.CentOS = &gometakube.NodeDeploymentSpecTemplateOSOptions{DistUpgradeOnBoot: &distUpgradeOnBoot}
+	case nodeOSContainerLinux:
+		ret.ContainerLinux = &gometakube.NodeDeploymentSpecTemplateOSOptions{DisableAutoUpdate: &disableAutoUpdate}
+	case nodeOSFlatcar:
+		ret.Flatcar = &gometakube.NodeDeploymentSpecTemplateOSOptions{DisableAutoUpdate: &disableAutoUpdate}
+	default:
+		ret.Ubuntu = &gometakube.NodeDeploymentSpecTemplateOSOptions{DistUpgradeOnBoot: &distUpgradeOnBoot}
+	}
+	return ret
+}
+
+func nodeDeploymentOSMap(os gometakube.NodeDeploymentSpecTemplateOS) []interface{} {
+	name, options := "", (*gometakube.NodeDeploymentSpecTemplateOSOptions)(nil)
+	switch {
+	case os.Ubuntu != nil:
+		name, options = nodeOSUbuntu, os.Ubuntu
+	case os.CentOS != nil:
+		name, options = nodeOSCentOS, os.CentOS
+	case os.ContainerLinux != nil:
+		name, options = nodeOSContainerLinux, os.ContainerLinux
+	case os.Flatcar != nil:
+		name, options = nodeOSFlatcar, os.Flatcar
+	default:
+		return []interface{}{}
+	}
+	return []interface{}{map[string]interface{}{
+		"name":                 name,
+		"dist_upgrade_on_boot": options.DistUpgradeOnBoot != nil && *options.DistUpgradeOnBoot,
+		"disable_auto_update":  options.DisableAutoUpdate != nil && *options.DisableAutoUpdate,
+	}}
+}
+
+// checkNodeDeploymentOS checks options apply to the operating system and image runs it.
+func checkNodeDeploymentOS(nodedepl map[string]interface{}, image *gometakube.Image) error {
+	l, ok := nodedepl["operating_system"].([]interface{})
+	if !ok || len(l) != 1 || l[0] == nil {
+		return nil
+	}
+	v := l[0].(map[string]interface{})
+	name := v["name"].(string)
+	switch name {
+	case nodeOSUbuntu, nodeOSCentOS:
+		if v["disable_auto_update"].(bool) {
+			return errors.Errorf("disable_auto_update is not supported by operating system `%s`", name)
+		}
+	case nodeOSContainerLinux, nodeOSFlatcar:
+		if v["dist_upgrade_on_boot"].(bool) {
+			return errors.Errorf("dist_upgrade_on_boot is not supported by operating system `%s`", name)
+		}
+	}
+	distro := strings.ToLower(image.Metadata.OSDistro)
+	if distro == "" {
+		// Nothing to check against, e.g. custom images without metadata.
+		return nil
+	}
+	for _, item := range nodeOSImageDistros[name] {
+		if strings.Contains(distro, item) {
+			return nil
+		}
+	}
+	return errors.Errorf("image `%s` runs `%s`, it cannot be used with operating system `%s`", image.Name, image.Metadata.OSDistro, name)
+}
+
+// nodeDeploymentOpenstack returns openstack spec of nodedepl.
+// Instance tags MetaKube sets in current are kept.
+func nodeDeploymentOpenstack(nodedepl map[string]interface{}, current *gometakube.NodeDeploymentSpecTemplateCloudOpenstack) gometakube.NodeDeploymentSpecTemplateCloudOpenstack {
+	ret := gometakube.NodeDeploymentSpecTemplateCloudOpenstack{
+		Flavor:             nodedepl["flavor"].(string),
+		Image:              nodedepl["image"].(string),
+		UseFloatingIP:      nodedepl["use_floating_ip"].(bool),
+		AvailabilityZone:   nodedepl["availability_zone"].(string),
+		RootDiskVolumeType: nodedepl["root_volume_type"].(string),
+		Tags:               make(map[string]string),
+	}
+	if v := nodedepl["disk_size"].(int); v != 0 {
+		ret.DiskSize = &v
+	}
+	if current != nil {
+		for k, v := range current.Tags {
+			if nodeSystemTag(k) {
+				ret.Tags[k] = v
+			}
+		}
+	}
+	for k, v := range nodedepl["tags"].(map[string]interface{}) {
+		ret.Tags[k] = v.(string)
+	}
+	return ret
+}
+
+// nodeDeploymentUserTags returns tags without those MetaKube sets itself.
+func nodeDeploymentUserTags(tags map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range tags {
+		if !nodeSystemTag(k) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func nodeSystemTag(k string) bool {
+	return k == nodeSystemTagKey || strings.HasPrefix(k, nodeSystemTagPrefix)
+}
+
+// nodeDeploymentLabels returns node labels of nodedepl.
+// Labels MetaKube sets in current are kept.
+func nodeDeploymentLabels(nodedepl map[string]interface{}, current map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range current {
+		if strings.HasPrefix(k, nodeSystemLabelPrefix) {
+			ret[k] = v
+		}
+	}
+	for k, v := range nodedepl["node_labels"].(map[string]interface{}) {
+		ret[k] = v.(string)
+	}
+	return ret
+}
+
+// nodeDeploymentUserLabels returns labels without those MetaKube sets itself.
+func nodeDeploymentUserLabels(labels map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range labels {
+		if !strings.HasPrefix(k, nodeSystemLabelPrefix) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func nodeDeploymentTaints(nodedepl map[string]interface{}) []gometakube.TaintSpec {
+	ret := make([]gometakube.TaintSpec, 0)
+	for _, item := range nodedepl["taints"].([]interface{}) {
+		v := item.(map[string]interface{})
+		ret = append(ret, gometakube.TaintSpec{
+			Key:    v["key"].(string),
+			Value:  v["value"].(string),
+			Effect: v["effect"].(string),
+		})
+	}
+	return ret
+}
+
+func nodeDeploymentTaintsList(taints []gometakube.TaintSpec) []interface{} {
+	ret := make([]interface{}, 0)
+	for _, item := range taints {
+		ret = append(ret, map[string]interface{}{
+			"key":    item.Key,
+			"value":  item.Value,
+			"effect": item.Effect,
+		})
+	}
+	return ret
+}
+
+func nodeDeploymentKubelet(nodedepl map[string]interface{}) *gometakube.NodeDeploymentSpecTemplateKubelet {
+	l, ok := nodedepl["kubelet"].([]interface{})
+	if !ok || len(l) != 1 || l[0] == nil {
+		return nil
+	}
+	v := l[0].(map[string]interface{})
+	ret := &gometakube.NodeDeploymentSpecTemplateKubelet{
+		EvictionHard:            stringsMap(v["eviction_hard"]),
+		EvictionSoft:            stringsMap(v["eviction_soft"]),
+		EvictionSoftGracePeriod: stringsMap(v["eviction_soft_grace_period"]),
+	}
+	if maxPods := v["max_pods"].(int); maxPods != 0 {
+		ret.MaxPods = &maxPods
+	}
+	return ret
+}
+
+func nodeDeploymentKubeletMap(kubelet *gometakube.NodeDeploymentSpecTemplateKubelet) []interface{} {
+	if kubelet == nil {
+		return []interface{}{}
+	}
+	maxPods := 0
+	if kubelet.MaxPods != nil {
+		maxPods = *kubelet.MaxPods
+	}
+	return []interface{}{map[string]interface{}{
+		"max_pods":                   maxPods,
+		"eviction_hard":              kubelet.EvictionHard,
+		"eviction_soft":              kubelet.EvictionSoft,
+		"eviction_soft_grace_period": kubelet.EvictionSoftGracePeriod,
+	}}
+}
+
+func stringsMap(v interface{}) map[string]string {
+	ret := make(map[string]string)
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, item := range m {
+			ret[k] = item.(string)
+		}
+	}
+	return ret
+}
+
+// nodeDeploymentStrategy returns rolling update strategy of nodedepl, nil leaves api defaults.
+func nodeDeploymentStrategy(nodedepl map[string]interface{}) *gometakube.NodeDeploymentStrategy {
+	l, ok := nodedepl["rollout_strategy"].([]interface{})
+	if !ok || len(l) != 1 || l[0] == nil {
+		return nil
+	}
+	v := l[0].(map[string]interface{})
+	rollingUpdate := &gometakube.NodeDeploymentStrategyRollingUpdate{}
+	if s := v["max_surge"].(string); s != "" {
+		maxSurge := gometakube.IntOrString(s)
+		rollingUpdate.MaxSurge = &maxSurge
+	}
+	if s := v["max_unavailable"].(string); s != "" {
+		maxUnavailable := gometakube.IntOrString(s)
+		rollingUpdate.MaxUnavailable = &maxUnavailable
+	}
+	return &gometakube.NodeDeploymentStrategy{
+		Type:          gometakube.NodeDeploymentStrategyTypeRollingUpdate,
+		RollingUpdate: rollingUpdate,
+	}
+}
+
+func nodeDeploymentStrategyMap(strategy *gometakube.NodeDeploymentStrategy) []interface{} {
+	if strategy == nil || strategy.RollingUpdate == nil {
+		return []interface{}{}
+	}
+	ret := map[string]interface{}{
+		"max_surge":       "",
+		"max_unavailable": "",
+	}
+	if strategy.RollingUpdate.MaxSurge != nil {
+		ret["max_surge"] = string(*strategy.RollingUpdate.MaxSurge)
+	}
+	if strategy.RollingUpdate.MaxUnavailable != nil {
+		ret["max_unavailable"] = string(*strategy.RollingUpdate.MaxUnavailable)
+	}
+	return []interface{}{ret}
+}
+
+var intOrPercentageRegexp = regexp.MustCompile(`^(0|[1-9][0-9]*)%?$`)
+
+func validateIntOrPercentage(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{errors.Errorf("expected type of %s to be string", k)}
+	}
+	if !intOrPercentageRegexp.MatchString(v) {
+		return nil, []error{errors.Errorf("%s: expected number of nodes or percentage, e.g. 1 or 25%%, got `%s`", k, v)}
+	}
+	return nil, nil
+}
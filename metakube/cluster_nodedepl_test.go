@@ -0,0 +1,182 @@
+package metakube
+
+import (
+	"reflect"
+	"testing"
+
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func TestNodeDeploymentOS(t *testing.T) {
+	os := nodeDeploymentOS(map[string]interface{}{})
+	if os.Ubuntu == nil || *os.Ubuntu.DistUpgradeOnBoot {
+		t.Fatalf("want ubuntu without dist upgrade by default, got %+v", os)
+	}
+
+	nodedepl := map[string]interface{}{
+		"operating_system": []interface{}{map[string]interface{}{
+			"name":                 nodeOSFlatcar,
+			"dist_upgrade_on_boot": false,
+			"disable_auto_update":  true,
+		}},
+	}
+	os = nodeDeploymentOS(nodedepl)
+	if os.Flatcar == nil || !*os.Flatcar.DisableAutoUpdate {
+		t.Fatalf("want flatcar with auto update disabled, got %+v", os)
+	}
+	if want, got := nodedepl["operating_system"], nodeDeploymentOSMap(os); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestCheckNodeDeploymentOS(t *testing.T) {
+	ubuntuImage := &gometakube.Image{Name: "Ubuntu Bionic", Metadata: gometakube.ImageMetadata{OSDistro: "ubuntu"}}
+	for _, tc := range []struct {
+		name    string
+		os      map[string]interface{}
+		image   *gometakube.Image
+		wantErr bool
+	}{
+		{
+			name:  "not configured",
+			image: ubuntuImage,
+		},
+		{
+			name:  "matches image",
+			os:    map[string]interface{}{"name": nodeOSUbuntu, "dist_upgrade_on_boot": true, "disable_auto_update": false},
+			image: ubuntuImage,
+		},
+		{
+			name:  "image without metadata",
+			os:    map[string]interface{}{"name": nodeOSCentOS, "dist_upgrade_on_boot": false, "disable_auto_update": false},
+			image: &gometakube.Image{Name: "custom"},
+		},
+		{
+			name:    "image runs other distro",
+			os:      map[string]interface{}{"name": nodeOSFlatcar, "dist_upgrade_on_boot": false, "disable_auto_update": false},
+			image:   ubuntuImage,
+			wantErr: true,
+		},
+		{
+			name:    "option not supported",
+			os:      map[string]interface{}{"name": nodeOSUbuntu, "dist_upgrade_on_boot": false, "disable_auto_update": true},
+			image:   ubuntuImage,
+			wantErr: true,
+		},
+	} {
+		nodedepl := map[string]interface{}{}
+		if tc.os != nil {
+			nodedepl["operating_system"] = []interface{}{tc.os}
+		}
+		err := checkNodeDeploymentOS(nodedepl, tc.image)
+		if tc.wantErr != (err != nil) {
+			t.Fatalf("%s: want error %v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestNodeDeploymentOpenstack(t *testing.T) {
+	nodedepl := map[string]interface{}{
+		"flavor":            "m1.small",
+		"image":             "Ubuntu Bionic",
+		"use_floating_ip":   false,
+		"availability_zone": "dbl1",
+		"root_volume_type":  "ssd",
+		"disk_size":         50,
+		"tags":              map[string]interface{}{"cost-center": "42"},
+	}
+	current := &gometakube.NodeDeploymentSpecTemplateCloudOpenstack{
+		Tags: map[string]string{
+			"metakube-cluster": "j7f2svjll8",
+			"system-project":   "5hrnkmpmp4",
+			"cost-center":      "41",
+			"removed":          "tag",
+		},
+	}
+	got := nodeDeploymentOpenstack(nodedepl, current)
+	want := gometakube.NodeDeploymentSpecTemplateCloudOpenstack{
+		Flavor:             "m1.small",
+		Image:              "Ubuntu Bionic",
+		AvailabilityZone:   "dbl1",
+		RootDiskVolumeType: "ssd",
+		DiskSize:           got.DiskSize,
+		Tags: map[string]string{
+			"metakube-cluster": "j7f2svjll8",
+			"system-project":   "5hrnkmpmp4",
+			"cost-center":      "42",
+		},
+	}
+	if got.DiskSize == nil || *got.DiskSize != 50 {
+		t.Fatalf("want disk size 50, got %v", got.DiskSize)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+	if want, got := map[string]string{"cost-center": "42"}, nodeDeploymentUserTags(got.Tags); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want user tags %v, got %v", want, got)
+	}
+}
+
+func TestNodeDeploymentLabelsTaintsKubelet(t *testing.T) {
+	nodedepl := map[string]interface{}{
+		"node_labels": map[string]interface{}{"role": "ingress"},
+		"taints": []interface{}{map[string]interface{}{
+			"key":    "dedicated",
+			"value":  "ingress",
+			"effect": gometakube.TaintEffectNoSchedule,
+		}},
+		"kubelet": []interface{}{map[string]interface{}{
+			"max_pods":                   50,
+			"eviction_hard":              map[string]interface{}{"memory.available": "200Mi"},
+			"eviction_soft":              map[string]interface{}{},
+			"eviction_soft_grace_period": map[string]interface{}{},
+		}},
+	}
+
+	labels := nodeDeploymentLabels(nodedepl, map[string]string{"system/cluster": "j7f2svjll8", "role": "old"})
+	if want := map[string]string{"system/cluster": "j7f2svjll8", "role": "ingress"}; !reflect.DeepEqual(want, labels) {
+		t.Fatalf("want labels %v, got %v", want, labels)
+	}
+	if want, got := map[string]string{"role": "ingress"}, nodeDeploymentUserLabels(labels); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want user labels %v, got %v", want, got)
+	}
+
+	if want, got := nodedepl["taints"], nodeDeploymentTaintsList(nodeDeploymentTaints(nodedepl)); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want taints %v, got %v", want, got)
+	}
+
+	kubelet := nodeDeploymentKubelet(nodedepl)
+	if kubelet.MaxPods == nil || *kubelet.MaxPods != 50 {
+		t.Fatalf("want max pods 50, got %v", kubelet.MaxPods)
+	}
+	if want, got := "200Mi", kubelet.EvictionHard["memory.available"]; want != got {
+		t.Fatalf("want eviction hard memory %s, got %s", want, got)
+	}
+	if got := nodeDeploymentKubelet(map[string]interface{}{"kubelet": []interface{}{}}); got != nil {
+		t.Fatalf("want no kubelet config, got %+v", got)
+	}
+}
+
+func TestNodeDeploymentStrategy(t *testing.T) {
+	if got := nodeDeploymentStrategy(map[string]interface{}{"rollout_strategy": []interface{}{}}); got != nil {
+		t.Fatalf("want api default strategy, got %+v", got)
+	}
+	nodedepl := map[string]interface{}{
+		"rollout_strategy": []interface{}{map[string]interface{}{
+			"max_surge":       "1",
+			"max_unavailable": "25%",
+		}},
+	}
+	strategy := nodeDeploymentStrategy(nodedepl)
+	if want, got := gometakube.NodeDeploymentStrategyTypeRollingUpdate, strategy.Type; want != got {
+		t.Fatalf("want strategy type %s, got %s", want, got)
+	}
+	if want, got := nodedepl["rollout_strategy"], nodeDeploymentStrategyMap(strategy); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for v, wantErr := range map[string]bool{"1": false, "25%": false, "": true, "-1": true, "1.5": true, "%": true, "0": false, "007": true, "05%": true} {
+		if _, errs := validateIntOrPercentage(v, "max_surge"); wantErr != (len(errs) != 0) {
+			t.Fatalf("%q: want error %v, got %v", v, wantErr, errs)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package metakube
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func dataSourceClusterMetrics() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceClusterMetricsRead,
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"dc": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"control_plane_cpu_total_millicores": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"control_plane_memory_total_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"nodes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: usageMetricsSchema(),
+				},
+			},
+			"node_deployments": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: nodeDeploymentMetricsSchema(),
+				},
+			},
+		},
+	}
+}
+
+func usageMetricsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cpu_total_millicores": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"cpu_available_millicores": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"cpu_used_percentage": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"memory_total_bytes": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"memory_available_bytes": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"memory_used_percentage": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+	}
+}
+
+func nodeDeploymentMetricsSchema() map[string]*schema.Schema {
+	ret := usageMetricsSchema()
+	ret["name"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	ret["node_count"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	}
+	return ret
+}
+
+func dataSourceClusterMetricsRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	metrics, _, err := client.Clusters.Metrics(ctx, prj, dc.Spec.Seed, cls)
+	if err != nil {
+		return errors.Wrap(err, "get cluster metrics")
+	}
+	nodedepls, _, err := client.NodeDeployments.List(ctx, prj, dc.Spec.Seed, cls)
+	if err != nil {
+		return errors.Wrap(err, "list node deployments")
+	}
+	nodedeplsMetrics := make([]interface{}, 0)
+	for _, nodedepl := range nodedepls {
+		nodes, _, err := client.NodeDeployments.Metrics(ctx, prj, dc.Spec.Seed, cls, nodedepl.ID)
+		if err != nil {
+			return errors.Wrapf(err, "get node deployment `%s` metrics", nodedepl.Name)
+		}
+		item := usageMetricsMap(aggregateNodeMetrics(nodes))
+		item["name"] = nodedepl.Name
+		item["node_count"] = len(nodes)
+		nodedeplsMetrics = append(nodedeplsMetrics, item)
+	}
+
+	d.SetId(cls)
+	d.Set("control_plane_cpu_total_millicores", metrics.ControlPlane.CPUTotalMillicores)
+	d.Set("control_plane_memory_total_bytes", metrics.ControlPlane.MemoryTotalBytes)
+	d.Set("nodes", []interface{}{usageMetricsMap(&metrics.Nodes)})
+	d.Set("node_deployments", nodedeplsMetrics)
+	return nil
+}
+
+// aggregateNodeMetrics sums nodes resources and calculates used percentage of the sum.
+func aggregateNodeMetrics(nodes []gometakube.NodeMetric) *gometakube.NodesMetric {
+	ret := new(gometakube.NodesMetric)
+	for _, n := range nodes {
+		ret.CPUTotalMillicores += n.CPUTotalMillicores
+		ret.CPUAvailableMillicores += n.CPUAvailableMillicores
+		ret.MemoryTotalBytes += n.MemoryTotalBytes
+		ret.MemoryAvailableBytes += n.MemoryAvailableBytes
+	}
+	if ret.CPUTotalMillicores > 0 {
+		ret.CPUUsedPercentage = (ret.CPUTotalMillicores - ret.CPUAvailableMillicores) * 100 / ret.CPUTotalMillicores
+	}
+	if ret.MemoryTotalBytes > 0 {
+		ret.MemoryUsedPercentage = (ret.MemoryTotalBytes - ret.MemoryAvailableBytes) * 100 / ret.MemoryTotalBytes
+	}
+	return ret
+}
+
+func usageMetricsMap(v *gometakube.NodesMetric) map[string]interface{} {
+	return map[string]interface{}{
+		"cpu_total_millicores":     v.CPUTotalMillicores,
+		"cpu_available_millicores": v.CPUAvailableMillicores,
+		"cpu_used_percentage":      v.CPUUsedPercentage,
+		"memory_total_bytes":       v.MemoryTotalBytes,
+		"memory_available_bytes":   v.MemoryAvailableBytes,
+		"memory_used_percentage":   v.MemoryUsedPercentage,
+	}
+}
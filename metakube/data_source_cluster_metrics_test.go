@@ -0,0 +1,99 @@
+package metakube
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func testAccMetakubeClusterMetricsConfig(project, dc, tenant, username, password string) string {
+	return testAccMetakubeClusterConfig(project, dc, tenant, username, password) + `
+data "metakube_cluster_metrics" "bar" {
+	project_id = metakube_project.cluster-project.id
+	dc = metakube_cluster.bar.dc
+	cluster_id = metakube_cluster.bar.id
+}
+`
+}
+
+func TestAccMetakubeClusterMetrics_Basic(t *testing.T) {
+	testDC := os.Getenv(accProviderDCEnvname)
+	testTenant := os.Getenv(accTenantEnvname)
+	testProviderUsername := os.Getenv(accProviderUsernameEnvname)
+	testProviderPassword := os.Getenv(accProviderPasswordEnvname)
+	projectName := acctest.RandString(8)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testEnvSet(t, APITokenEnvName)
+			testEnvSet(t, accProviderDCEnvname)
+			testEnvSet(t, accTenantEnvname)
+			testEnvSet(t, accProviderUsernameEnvname)
+			testEnvSet(t, accProviderPasswordEnvname)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetakubeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetakubeClusterMetricsConfig(projectName, testDC, testTenant, testProviderUsername, testProviderPassword),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.metakube_cluster_metrics.bar", "control_plane_cpu_total_millicores"),
+					resource.TestCheckResourceAttr("data.metakube_cluster_metrics.bar", "nodes.#", "1"),
+					resource.TestCheckResourceAttr("data.metakube_cluster_metrics.bar", "node_deployments.#", "1"),
+					resource.TestCheckResourceAttr("data.metakube_cluster_metrics.bar", "node_deployments.0.name", "my-nodedepl"),
+					resource.TestCheckResourceAttr("data.metakube_cluster_metrics.bar", "node_deployments.0.node_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAggregateNodeMetrics(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		nodes []gometakube.NodeMetric
+		want  gometakube.NodesMetric
+	}{
+		{
+			name: "no nodes",
+			want: gometakube.NodesMetric{},
+		},
+		{
+			name: "single node",
+			nodes: []gometakube.NodeMetric{
+				{Name: "a", CPUTotalMillicores: 2000, CPUAvailableMillicores: 500, MemoryTotalBytes: 4096, MemoryAvailableBytes: 1024},
+			},
+			want: gometakube.NodesMetric{
+				CPUTotalMillicores: 2000, CPUAvailableMillicores: 500, CPUUsedPercentage: 75,
+				MemoryTotalBytes: 4096, MemoryAvailableBytes: 1024, MemoryUsedPercentage: 75,
+			},
+		},
+		{
+			name: "several nodes",
+			nodes: []gometakube.NodeMetric{
+				{Name: "a", CPUTotalMillicores: 2000, CPUAvailableMillicores: 2000, MemoryTotalBytes: 4096, MemoryAvailableBytes: 4096},
+				{Name: "b", CPUTotalMillicores: 2000, CPUAvailableMillicores: 0, MemoryTotalBytes: 4096, MemoryAvailableBytes: 2048},
+				{Name: "c", CPUTotalMillicores: 4000, CPUAvailableMillicores: 1000, MemoryTotalBytes: 8192, MemoryAvailableBytes: 0},
+			},
+			want: gometakube.NodesMetric{
+				CPUTotalMillicores: 8000, CPUAvailableMillicores: 3000, CPUUsedPercentage: 62,
+				MemoryTotalBytes: 16384, MemoryAvailableBytes: 6144, MemoryUsedPercentage: 62,
+			},
+		},
+		{
+			name: "nodes without capacity reported",
+			nodes: []gometakube.NodeMetric{
+				{Name: "a"},
+				{Name: "b"},
+			},
+			want: gometakube.NodesMetric{},
+		},
+	} {
+		if got := aggregateNodeMetrics(tc.nodes); !reflect.DeepEqual(&tc.want, got) {
+			t.Fatalf("%s: want %+v, got %+v", tc.name, tc.want, *got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package metakube
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	labelNameMaxLength   = 63
+	labelPrefixMaxLength = 253
+)
+
+var (
+	labelNameRegexp   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	labelPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// validateLabels checks keys and values of labels map follow kubernetes label syntax.
+func validateLabels(i interface{}, k string) ([]string, []error) {
+	labels, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be map", k)}
+	}
+	var errs []error
+	for key, v := range labels {
+		if err := validateLabelKey(key); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid key `%s`: %v", k, key, err))
+		}
+		value, ok := v.(string)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: value of `%s` must be string", k, key))
+		} else if err := validateLabelValue(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value `%s` of `%s`: %v", k, value, key, err))
+		}
+	}
+	return nil, errs
+}
+
+func validateLabelKey(key string) error {
+	name := key
+	if i := strings.LastIndex(key, "/"); i != -1 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) > labelPrefixMaxLength {
+			return fmt.Errorf("prefix must be no more than %d characters", labelPrefixMaxLength)
+		}
+		if !labelPrefixRegexp.MatchString(prefix) {
+			return fmt.Errorf("prefix must be a DNS subdomain")
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	return validateLabelName(name)
+}
+
+func validateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	return validateLabelName(value)
+}
+
+func validateLabelName(v string) error {
+	if len(v) > labelNameMaxLength {
+		return fmt.Errorf("must be no more than %d characters", labelNameMaxLength)
+	}
+	if !labelNameRegexp.MatchString(v) {
+		return fmt.Errorf("must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character")
+	}
+	return nil
+}
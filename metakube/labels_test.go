@@ -0,0 +1,26 @@
+package metakube
+
+import "testing"
+
+func TestValidateLabels(t *testing.T) {
+	valid := map[string]interface{}{
+		"team":                    "platform",
+		"example.com/owner":       "a.b_c-d",
+		"app.kubernetes.io/empty": "",
+	}
+	if _, errs := validateLabels(valid, "labels"); len(errs) != 0 {
+		t.Fatalf("want no errors, got: %v", errs)
+	}
+	for _, labels := range []map[string]interface{}{
+		{"-team": "platform"},
+		{"Example.com/owner": "me"},
+		{"example.com/": "me"},
+		{"team": "has space"},
+		{"team": "trailing-"},
+		{"team": "a123456789012345678901234567890123456789012345678901234567890123"},
+	} {
+		if _, errs := validateLabels(labels, "labels"); len(errs) == 0 {
+			t.Errorf("want error for %v", labels)
+		}
+	}
+}
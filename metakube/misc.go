@@ -1,11 +1,15 @@
 package metakube
 
 import (
+	"context"
+	"encoding/json"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
 )
 
 func labelsMap(d *schema.ResourceData) (ret map[string]string) {
@@ -63,3 +67,96 @@ func clusterVersionBigger(a, b string) (bool, error) {
 		return false, nil
 	}
 }
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Refuse to delete, set to false and apply before destroying or replacing.",
+	}
+}
+
+func checkDeletionProtection(d *schema.ResourceData, kind string) error {
+	if d.Get("deletion_protection").(bool) {
+		return errors.Errorf("%s `%s` has deletion_protection enabled, set it to false and apply before deleting", kind, d.Id())
+	}
+	return nil
+}
+
+func preventReplacementSchema(forceNew []string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Attributes which changes must fail plan instead of replacing the resource.",
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice(forceNew, false),
+		},
+	}
+}
+
+// checkPreventReplacement fails plan when an attribute listed in prevent_replacement_of changes on existing resource.
+func checkPreventReplacement(d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, v := range d.Get("prevent_replacement_of").(*schema.Set).List() {
+		if k := v.(string); d.HasChange(k) {
+			old, new := d.GetChange(k)
+			return errors.Errorf("change of %s from `%v` to `%v` replaces the resource, but it is listed in prevent_replacement_of", k, old, new)
+		}
+	}
+	return nil
+}
+
+// conflictError explains failed conditional update with changes made on server side since the resource was read,
+// read are the fields as they were read before the update, current as they are now.
+func conflictError(kind string, err error, read, current interface{}) error {
+	diff, derr := gometakube.NewMergePatch(read, current)
+	if derr != nil {
+		return errors.Wrapf(err, "%s was changed concurrently", kind)
+	}
+	b, derr := json.Marshal(diff)
+	if derr != nil {
+		return errors.Wrapf(err, "%s was changed concurrently", kind)
+	}
+	return errors.Errorf("%s was changed concurrently, refresh and review the changes before applying again, changed on server: %s", kind, b)
+}
+
+// updateIfUnchanged runs update with etag read into state as If-Match precondition, so that changes made
+// concurrently, e.g. in the UI, are not overwritten. If the resource changed on server since it was read,
+// but fields read into state did not, e.g. only its status did, update is retried with the current etag,
+// otherwise the error lists changes made on server. read are the fields as they were read into state,
+// current returns them as they are now, along with the current etag.
+func updateIfUnchanged(ctx context.Context, kind, etag string, read interface{}, current func() (interface{}, string, error), update func(ctx context.Context) error) error {
+	err := update(gometakube.WithIfMatch(ctx, etag))
+	if !gometakube.IsConflict(err) {
+		return err
+	}
+	now, currentETag, cerr := current()
+	if cerr != nil {
+		return errors.Wrapf(err, "%s was changed concurrently", kind)
+	}
+	if diff, derr := gometakube.NewMergePatch(read, now); derr != nil || !diff.Empty() {
+		return conflictError(kind, err, read, now)
+	}
+	return update(gometakube.WithIfMatch(ctx, currentETag))
+}
+
+// firstListItem returns the item of a list block with at most one item, nil if the list is empty.
+func firstListItem(v interface{}) interface{} {
+	if items, ok := v.([]interface{}); ok && len(items) != 0 {
+		return items[0]
+	}
+	return nil
+}
+
+// stateSetDefaults sets defaults of attributes missing in raw state, used by state upgraders.
+func stateSetDefaults(rawState map[string]interface{}, defaults map[string]interface{}) {
+	for k, v := range defaults {
+		if rawState[k] == nil {
+			rawState[k] = v
+		}
+	}
+}
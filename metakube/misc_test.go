@@ -0,0 +1,83 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func TestCheckDeletionProtection(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceProject().Schema, map[string]interface{}{
+		"name":                "foo",
+		"deletion_protection": true,
+	})
+	d.SetId("theproject")
+	if err := checkDeletionProtection(d, "project"); err == nil {
+		t.Fatal("want error deleting protected project")
+	}
+	d.Set("deletion_protection", false)
+	if err := checkDeletionProtection(d, "project"); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	read := &gometakube.ProjectCreateAndUpdateRequest{Name: "prj", Labels: map[string]string{"team": "a"}}
+	current := &gometakube.ProjectCreateAndUpdateRequest{Name: "prj", Labels: map[string]string{"team": "b"}}
+	err := conflictError("project", errors.New("412 Precondition Failed"), read, current)
+	if want := `changed on server: {"labels":{"team":"b"}}`; !strings.HasSuffix(err.Error(), want) {
+		t.Fatalf("want error ending with %s, got: %v", want, err)
+	}
+}
+
+func TestUpdateIfUnchanged(t *testing.T) {
+	var ifMatch []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/projects/theproject", func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+		if r.Header.Get("If-Match") != "v2" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		fmt.Fprint(w, `{"id": "theproject", "name": "prj"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := gometakube.New()
+	client.BaseURL, _ = url.Parse(server.URL)
+	update := func(ctx context.Context) error {
+		_, _, err := client.Projects.Update(ctx, "theproject", &gometakube.ProjectCreateAndUpdateRequest{Name: "prj"})
+		return err
+	}
+	read := &gometakube.ProjectCreateAndUpdateRequest{Name: "prj", Labels: map[string]string{"team": "a"}}
+
+	// Only fields not in state changed since read, update is retried with current etag.
+	err := updateIfUnchanged(context.Background(), "project", "v1", read, func() (interface{}, string, error) {
+		return &gometakube.ProjectCreateAndUpdateRequest{Name: "prj", Labels: map[string]string{"team": "a"}}, "v2", nil
+	}, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "[v1 v2]", fmt.Sprint(ifMatch); want != got {
+		t.Fatalf("want If-Match %s, got %s", want, got)
+	}
+
+	ifMatch = nil
+	err = updateIfUnchanged(context.Background(), "project", "v1", read, func() (interface{}, string, error) {
+		return &gometakube.ProjectCreateAndUpdateRequest{Name: "prj", Labels: map[string]string{"team": "b"}}, "v2", nil
+	}, update)
+	if want := `changed on server: {"labels":{"team":"b"}}`; err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Fatalf("want error ending with %s, got: %v", want, err)
+	}
+	if want, got := "[v1]", fmt.Sprint(ifMatch); want != got {
+		t.Fatalf("want If-Match %s, got %s", want, got)
+	}
+}
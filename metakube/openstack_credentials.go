@@ -0,0 +1,248 @@
+package metakube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OpenStack environment variables, the same the openstack cli uses.
+const (
+	osUsernameEnvName                    = "OS_USERNAME"
+	osPasswordEnvName                    = "OS_PASSWORD"
+	osApplicationCredentialIDEnvName     = "OS_APPLICATION_CREDENTIAL_ID"
+	osApplicationCredentialSecretEnvName = "OS_APPLICATION_CREDENTIAL_SECRET"
+	osCloudEnvName                       = "OS_CLOUD"
+	osCloudsYAMLEnvName                  = "OS_CLIENT_CONFIG_FILE"
+)
+
+const openstackDomain = "Default"
+
+func providerOpenstackSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "OpenStack credentials used by clusters which do not specify their own.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"username": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"password": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"application_credential_id": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"application_credential_secret": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"cloud": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Name of the cloud in clouds.yaml to read credentials from.",
+				},
+				"clouds_yaml": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Path to clouds.yaml, standard locations are searched if not set.",
+				},
+			},
+		},
+	}
+}
+
+// providerOpenstackCredentials resolves credentials shared by all clusters.
+// Provider block goes first, credentials set in it before its cloud,
+// then environment variables and clouds.yaml cloud named by OS_CLOUD.
+// Returns nil if none are configured.
+func providerOpenstackCredentials(d *schema.ResourceData) (*gometakube.OpenstackCredentials, error) {
+	ret := &gometakube.OpenstackCredentials{
+		Domain:                      openstackDomain,
+		Username:                    d.Get("openstack.0.username").(string),
+		Password:                    d.Get("openstack.0.password").(string),
+		ApplicationCredentialID:     d.Get("openstack.0.application_credential_id").(string),
+		ApplicationCredentialSecret: d.Get("openstack.0.application_credential_secret").(string),
+	}
+	if openstackCredentialsSet(ret) {
+		return ret, validateOpenstackCredentials(ret)
+	}
+	if cloud := d.Get("openstack.0.cloud").(string); cloud != "" {
+		return providerCloudsYAMLCredentials(d, cloud)
+	}
+
+	ret.Username = os.Getenv(osUsernameEnvName)
+	ret.Password = os.Getenv(osPasswordEnvName)
+	ret.ApplicationCredentialID = os.Getenv(osApplicationCredentialIDEnvName)
+	ret.ApplicationCredentialSecret = os.Getenv(osApplicationCredentialSecretEnvName)
+	if openstackCredentialsSet(ret) {
+		return ret, validateOpenstackCredentials(ret)
+	}
+	if cloud := os.Getenv(osCloudEnvName); cloud != "" {
+		return providerCloudsYAMLCredentials(d, cloud)
+	}
+	return nil, nil
+}
+
+func providerCloudsYAMLCredentials(d *schema.ResourceData, cloud string) (*gometakube.OpenstackCredentials, error) {
+	path := d.Get("openstack.0.clouds_yaml").(string)
+	if path == "" {
+		path = os.Getenv(osCloudsYAMLEnvName)
+	}
+	ret, err := cloudsYAMLCredentials(path, cloud)
+	if err != nil {
+		return nil, err
+	}
+	return ret, validateOpenstackCredentials(ret)
+}
+
+// resourceGetter is implemented by both schema.ResourceData and schema.ResourceDiff.
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+// clusterOpenstackCredentials returns credentials set on a cluster, or provider level ones.
+func clusterOpenstackCredentials(d resourceGetter, meta *metakubeProviderMeta) (*gometakube.OpenstackCredentials, error) {
+	ret := &gometakube.OpenstackCredentials{
+		Domain:                      openstackDomain,
+		Username:                    d.Get("provider_username").(string),
+		Password:                    d.Get("provider_password").(string),
+		ApplicationCredentialID:     d.Get("application_credential_id").(string),
+		ApplicationCredentialSecret: d.Get("application_credential_secret").(string),
+	}
+	if openstackCredentialsSet(ret) {
+		return ret, validateOpenstackCredentials(ret)
+	}
+	if meta.openstack != nil {
+		return meta.openstack, nil
+	}
+	return nil, errors.New("no openstack credentials: set provider_username and provider_password or application credential on the cluster, " +
+		"configure openstack block of the provider, OS_* environment variables or OS_CLOUD with clouds.yaml")
+}
+
+// openstackCredentialsHash returns hash of credentials applied to a cluster, kept in state to detect rotation
+// of provider level credentials, which are not kept in state. Cluster ID salts it.
+func openstackCredentialsHash(clusterID string, c *gometakube.OpenstackCredentials) string {
+	h := sha256.New()
+	for _, v := range []string{clusterID, c.Username, c.Password, c.ApplicationCredentialID, c.ApplicationCredentialSecret} {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// rotateOpenstackCredentialsDiff plans rotation of the cluster openstack credentials when provider level ones changed:
+// hash of the credentials configured now is compared with the one of credentials applied to the cluster.
+func rotateOpenstackCredentialsDiff(d *schema.ResourceDiff, meta interface{}) error {
+	m, ok := meta.(*metakubeProviderMeta)
+	if d.Id() == "" || !ok {
+		return nil
+	}
+	if d.HasChange("provider_username") || d.HasChange("provider_password") ||
+		d.HasChange("application_credential_id") || d.HasChange("application_credential_secret") {
+		// New credentials may be unknown until apply.
+		return d.SetNewComputed("openstack_credentials_hash")
+	}
+	old := d.Get("openstack_credentials_hash").(string)
+	creds, err := clusterOpenstackCredentials(d, m)
+	if err != nil || creds == nil || old == "" {
+		// Missing or invalid credentials are reported by apply.
+		return nil
+	}
+	if hash := openstackCredentialsHash(d.Id(), creds); hash != old {
+		return d.SetNew("openstack_credentials_hash", hash)
+	}
+	return nil
+}
+
+func openstackCredentialsSet(c *gometakube.OpenstackCredentials) bool {
+	return c.Username != "" || c.Password != "" || c.ApplicationCredentialID != "" || c.ApplicationCredentialSecret != ""
+}
+
+func validateOpenstackCredentials(c *gometakube.OpenstackCredentials) error {
+	appCred := c.ApplicationCredentialID != "" || c.ApplicationCredentialSecret != ""
+	password := c.Username != "" || c.Password != ""
+	switch {
+	case appCred && password:
+		return errors.New("openstack username/password and application credential can't be used together")
+	case appCred && (c.ApplicationCredentialID == "" || c.ApplicationCredentialSecret == ""):
+		return errors.New("openstack application credential needs both id and secret")
+	case password && (c.Username == "" || c.Password == ""):
+		return errors.New("openstack credentials need both username and password")
+	}
+	return nil
+}
+
+type cloudsYAML struct {
+	Clouds map[string]struct {
+		Auth struct {
+			Username                    string `yaml:"username"`
+			Password                    string `yaml:"password"`
+			UserDomainName              string `yaml:"user_domain_name"`
+			ApplicationCredentialID     string `yaml:"application_credential_id"`
+			ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+		} `yaml:"auth"`
+	} `yaml:"clouds"`
+}
+
+// cloudsYAMLCredentials reads credentials of the cloud from clouds.yaml.
+// If path is empty, the file is searched where openstack cli looks for it.
+func cloudsYAMLCredentials(path, cloud string) (*gometakube.OpenstackCredentials, error) {
+	if path == "" {
+		path = findCloudsYAML()
+		if path == "" {
+			return nil, errors.Errorf("clouds.yaml not found to read cloud `%s` from", cloud)
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read clouds.yaml")
+	}
+	parsed := new(cloudsYAML)
+	if err := yaml.Unmarshal(data, parsed); err != nil {
+		return nil, errors.Wrapf(err, "parse %s", path)
+	}
+	v, ok := parsed.Clouds[cloud]
+	if !ok {
+		return nil, errors.Errorf("cloud `%s` not found in %s", cloud, path)
+	}
+	ret := &gometakube.OpenstackCredentials{
+		Domain:                      v.Auth.UserDomainName,
+		Username:                    v.Auth.Username,
+		Password:                    v.Auth.Password,
+		ApplicationCredentialID:     v.Auth.ApplicationCredentialID,
+		ApplicationCredentialSecret: v.Auth.ApplicationCredentialSecret,
+	}
+	if ret.Domain == "" {
+		ret.Domain = openstackDomain
+	}
+	return ret, nil
+}
+
+func findCloudsYAML() string {
+	candidates := []string{"clouds.yaml"}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".config", "openstack", "clouds.yaml"))
+	}
+	candidates = append(candidates, "/etc/openstack/clouds.yaml")
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	return ""
+}
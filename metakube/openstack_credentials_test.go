@@ -0,0 +1,151 @@
+package metakube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+const testCloudsYAML = `
+clouds:
+  password:
+    auth:
+      username: theuser
+      password: thepassword
+      user_domain_name: thedomain
+  appcred:
+    auth_type: v3applicationcredential
+    auth:
+      application_credential_id: theid
+      application_credential_secret: thesecret
+`
+
+func TestCloudsYAMLCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clouds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "clouds.yaml")
+	if err := ioutil.WriteFile(path, []byte(testCloudsYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cloudsYAMLCredentials(path, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &gometakube.OpenstackCredentials{Domain: "thedomain", Username: "theuser", Password: "thepassword"}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %+v, got: %+v", want, got)
+	}
+
+	got, err = cloudsYAMLCredentials(path, "appcred")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &gometakube.OpenstackCredentials{Domain: openstackDomain, ApplicationCredentialID: "theid", ApplicationCredentialSecret: "thesecret"}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %+v, got: %+v", want, got)
+	}
+
+	if _, err := cloudsYAMLCredentials(path, "missing"); err == nil {
+		t.Fatal("want error for missing cloud")
+	}
+}
+
+func TestValidateOpenstackCredentials(t *testing.T) {
+	for _, tc := range []struct {
+		creds gometakube.OpenstackCredentials
+		valid bool
+	}{
+		{gometakube.OpenstackCredentials{Username: "u", Password: "p"}, true},
+		{gometakube.OpenstackCredentials{ApplicationCredentialID: "i", ApplicationCredentialSecret: "s"}, true},
+		{gometakube.OpenstackCredentials{Username: "u"}, false},
+		{gometakube.OpenstackCredentials{ApplicationCredentialID: "i"}, false},
+		{gometakube.OpenstackCredentials{Username: "u", Password: "p", ApplicationCredentialID: "i", ApplicationCredentialSecret: "s"}, false},
+	} {
+		if err := validateOpenstackCredentials(&tc.creds); (err == nil) != tc.valid {
+			t.Fatalf("credentials %+v: want valid=%v, got err: %v", tc.creds, tc.valid, err)
+		}
+	}
+}
+
+func TestProviderOpenstackCredentialsPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clouds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "clouds.yaml")
+	if err := ioutil.WriteFile(path, []byte(testCloudsYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{osUsernameEnvName: "envuser", osPasswordEnvName: "envpassword"} {
+		old, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		if ok {
+			defer os.Setenv(k, old)
+		} else {
+			defer os.Unsetenv(k)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{"openstack": providerOpenstackSchema()}, map[string]interface{}{
+		"openstack": []interface{}{map[string]interface{}{"cloud": "appcred", "clouds_yaml": path}},
+	})
+	got, err := providerOpenstackCredentials(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "theid"; got.ApplicationCredentialID != want {
+		t.Fatalf("want cloud of provider block over environment: %+v", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, map[string]*schema.Schema{"openstack": providerOpenstackSchema()}, map[string]interface{}{})
+	got, err = providerOpenstackCredentials(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "envuser"; got.Username != want {
+		t.Fatalf("want environment credentials without provider block: %+v", got)
+	}
+}
+
+func TestRotateOpenstackCredentialsDiff(t *testing.T) {
+	applied := &gometakube.OpenstackCredentials{Username: "theuser", Password: "thepassword"}
+	state := &terraform.InstanceState{
+		ID: "thecluster",
+		Attributes: map[string]string{
+			"name":                       "my-cluster",
+			"openstack_credentials_hash": openstackCredentialsHash("thecluster", applied),
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "my-cluster"})
+
+	diff, err := resourceCluster().Diff(state, config, &metakubeProviderMeta{openstack: applied})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && diff.Attributes["openstack_credentials_hash"] != nil {
+		t.Fatalf("want no rotation of applied credentials, got %v", diff.Attributes["openstack_credentials_hash"])
+	}
+
+	rotated := &gometakube.OpenstackCredentials{ApplicationCredentialID: "theid", ApplicationCredentialSecret: "thesecret"}
+	diff, err = resourceCluster().Diff(state, config, &metakubeProviderMeta{openstack: rotated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.Attributes["openstack_credentials_hash"] == nil {
+		t.Fatal("want rotation of changed provider level credentials planned")
+	}
+	if want, got := openstackCredentialsHash("thecluster", rotated), diff.Attributes["openstack_credentials_hash"].New; want != got {
+		t.Fatalf("want hash of rotated credentials %s, got %s", want, got)
+	}
+}
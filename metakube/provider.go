@@ -1,6 +1,8 @@
 package metakube
 
 import (
+	"context"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
 )
@@ -12,22 +14,64 @@ const (
 
 // Provider returns MetaKube Provider.
 func Provider() *schema.Provider {
-	return &schema.Provider{
-		Schema: map[string]*schema.Schema{
-			"token": &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc(APITokenEnvName, nil),
-			},
-		},
+	providerSchema := providerAuthSchema()
+	providerSchema["openstack"] = providerOpenstackSchema()
+	p := &schema.Provider{
+		Schema: providerSchema,
 		ResourcesMap: map[string]*schema.Resource{
 			"metakube_project": resourceProject(),
 			"metakube_cluster": resourceCluster(),
 			"metakube_sshkey":  resourceSSHKey(),
+
+			"metakube_service_account":           resourceServiceAccount(),
+			"metakube_service_account_token":     resourceServiceAccountToken(),
+			"metakube_cluster_addon":             resourceClusterAddon(),
+			"metakube_cluster_role_binding":      resourceClusterRoleBinding(),
+			"metakube_role_binding":              resourceRoleBinding(),
+			"metakube_cluster_sshkey_attachment": resourceClusterSSHKeyAttachment(),
 		},
-		ConfigureFunc: func(d *schema.ResourceData) (interface{}, error) {
-			token := d.Get("token").(string)
-			return gometakube.NewClient(gometakube.WithBearerToken(token)), nil
+		DataSourcesMap: map[string]*schema.Resource{
+			"metakube_cluster_metrics": dataSourceClusterMetrics(),
 		},
 	}
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		ts, err := providerTokenSource(d)
+		if err != nil {
+			return nil, err
+		}
+		openstack, err := providerOpenstackCredentials(d)
+		if err != nil {
+			return nil, err
+		}
+		return &metakubeProviderMeta{
+			client:    gometakube.NewClient(gometakube.WithTokenSource(ts)),
+			openstack: openstack,
+			stopCtx:   p.StopContext(),
+		}, nil
+	}
+	return p
+}
+
+// metakubeProviderMeta is passed to resources as meta.
+type metakubeProviderMeta struct {
+	client *gometakube.Client
+
+	// OpenStack credentials for clusters which do not set their own, nil if not configured.
+	openstack *gometakube.OpenstackCredentials
+
+	// Cancelled when terraform is interrupted.
+	stopCtx context.Context
+}
+
+func (m *metakubeProviderMeta) stopContext() context.Context {
+	if m.stopCtx == nil {
+		return context.Background()
+	}
+	return m.stopCtx
+}
+
+// operationContext returns context of a resource operation,
+// cancelled when terraform is interrupted or the operation timeout, like schema.TimeoutCreate, expires.
+func operationContext(d *schema.ResourceData, meta interface{}, timeout string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(meta.(*metakubeProviderMeta).stopContext(), d.Timeout(timeout))
 }
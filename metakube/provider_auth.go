@@ -0,0 +1,134 @@
+package metakube
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+	"golang.org/x/oauth2"
+)
+
+// provider auth env.
+const (
+	APITokenFileEnvName     = "METAKUBE_API_TOKEN_FILE"
+	APIRefreshTokenEnvName  = "METAKUBE_REFRESH_TOKEN"
+	APIOIDCClientIDEnvName  = "METAKUBE_OIDC_CLIENT_ID"
+	APIOIDCTokenURLEnvName  = "METAKUBE_OIDC_TOKEN_URL"
+	defaultOIDCClientID     = "metakube"
+	providerAuthDescription = "one of token, token_file, refresh_token or exec"
+)
+
+func providerAuthSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"token": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			DefaultFunc: schema.EnvDefaultFunc(APITokenEnvName, nil),
+			Description: "Static bearer token.",
+		},
+		"token_file": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			DefaultFunc: schema.EnvDefaultFunc(APITokenFileEnvName, nil),
+			Description: "File to read bearer token from, re-read when the file changes.",
+		},
+		"refresh_token": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "OAuth2 refresh token flow against MetaKube identity provider.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"refresh_token": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Sensitive:   true,
+						DefaultFunc: schema.EnvDefaultFunc(APIRefreshTokenEnvName, nil),
+					},
+					"client_id": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						DefaultFunc: schema.EnvDefaultFunc(APIOIDCClientIDEnvName, defaultOIDCClientID),
+					},
+					"client_secret": {
+						Type:      schema.TypeString,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"token_url": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						DefaultFunc: schema.EnvDefaultFunc(APIOIDCTokenURLEnvName, gometakube.DefaultTokenURL),
+					},
+				},
+			},
+		},
+		"exec": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Credential helper command printing ExecCredential json, like kubectl exec plugins.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"command": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"args": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"env": {
+						Type:     schema.TypeMap,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+	}
+}
+
+// providerTokenSource selects token source configured in provider block.
+// exec, refresh_token and token_file take precedence over a static token,
+// since the token is often set in environment for other tooling.
+func providerTokenSource(d *schema.ResourceData) (oauth2.TokenSource, error) {
+	var ret []oauth2.TokenSource
+	if _, ok := d.GetOk("exec"); ok {
+		args := make([]string, 0)
+		for _, v := range d.Get("exec.0.args").([]interface{}) {
+			args = append(args, v.(string))
+		}
+		env := make(map[string]string)
+		for k, v := range d.Get("exec.0.env").(map[string]interface{}) {
+			env[k] = v.(string)
+		}
+		ret = append(ret, gometakube.NewExecTokenSource(gometakube.ExecConfig{
+			Command: d.Get("exec.0.command").(string),
+			Args:    args,
+			Env:     env,
+		}))
+	}
+	if _, ok := d.GetOk("refresh_token"); ok {
+		ret = append(ret, gometakube.NewRefreshTokenSource(gometakube.RefreshTokenConfig{
+			RefreshToken: d.Get("refresh_token.0.refresh_token").(string),
+			ClientID:     d.Get("refresh_token.0.client_id").(string),
+			ClientSecret: d.Get("refresh_token.0.client_secret").(string),
+			TokenURL:     d.Get("refresh_token.0.token_url").(string),
+		}))
+	}
+	if v, ok := d.GetOk("token_file"); ok {
+		ret = append(ret, gometakube.NewFileTokenSource(v.(string)))
+	}
+	switch {
+	case len(ret) > 1:
+		return nil, errors.Errorf("provider auth is ambiguous, configure %s", providerAuthDescription)
+	case len(ret) == 1:
+		return ret[0], nil
+	}
+	if v, ok := d.GetOk("token"); ok {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: v.(string)}), nil
+	}
+	return nil, errors.Errorf("provider auth is not configured, set %s (or %s environment variable)", providerAuthDescription, APITokenEnvName)
+}
@@ -2,22 +2,60 @@ package metakube
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
 	"net/http"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/pkg/errors"
 	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
 )
 
+// clusterForceNewAttributes are attributes which changes replace a cluster.
+var clusterForceNewAttributes = []string{"project_id", "dc", "tenant", "nodedepl.0.name"}
+
 func resourceCluster() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceClusterCreate,
 		Read:   resourceClusterRead,
 		Update: resourceClusterUpdate,
 		Delete: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceClusterImport,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceClusterV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceClusterStateUpgradeV0,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
+
+		CustomizeDiff: customdiff.All(
+			checkPreventReplacement,
+			customdiff.ComputedIf("effective_labels", func(d *schema.ResourceDiff, _ interface{}) bool {
+				return d.HasChange("labels")
+			}),
+			resumeClusterCreateDiff,
+			rotateOpenstackCredentialsDiff,
+			checkClusterOverridesLabels,
+		),
 
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -32,12 +70,33 @@ func resourceCluster() *schema.Resource {
 				ValidateFunc: validation.NoZeroValues,
 			},
 			"labels": {
-				Type:     schema.TypeMap,
-				Optional: true,
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateLabels,
+			},
+			"override_project_labels": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Keys of project labels which values are overridden in labels, if the api allows it.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"inherited_labels": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Labels inherited from project.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"effective_labels": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "All labels cluster has, own and inherited from project.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"sshkeys": {
-				Type:     schema.TypeSet,
-				Optional: true,
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "IDs of project ssh keys to assign, names are deprecated. Keys attached with metakube_cluster_sshkey_attachment are not listed.",
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
 					ValidateFunc: validation.NoZeroValues,
@@ -61,24 +120,114 @@ func resourceCluster() *schema.Resource {
 				ValidateFunc: validation.NoZeroValues,
 			},
 			"provider_username": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				Sensitive:    true,
-				ValidateFunc: validation.NoZeroValues,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ValidateFunc:  validation.NoZeroValues,
+				ConflictsWith: []string{"application_credential_id", "application_credential_secret"},
 			},
 			"provider_password": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				Sensitive:    true,
-				ValidateFunc: validation.NoZeroValues,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ValidateFunc:  validation.NoZeroValues,
+				ConflictsWith: []string{"application_credential_id", "application_credential_secret"},
+			},
+			"application_credential_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ValidateFunc:  validation.NoZeroValues,
+				ConflictsWith: []string{"provider_username", "provider_password"},
+			},
+			"application_credential_secret": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ValidateFunc:  validation.NoZeroValues,
+				ConflictsWith: []string{"provider_username", "provider_password"},
+			},
+			"openstack_credentials_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Hash of OpenStack credentials applied to the cluster, provider level ones are rotated when it changes.",
 			},
 			"audit_logging": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
 			},
+			"deletion_protection":    deletionProtectionSchema(),
+			"prevent_replacement_of": preventReplacementSchema(clusterForceNewAttributes),
+			"delete_volumes_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete volumes of persistent volume claims with the cluster.",
+			},
+			"delete_load_balancers_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete load balancers of LoadBalancer services with the cluster.",
+			},
+			"wait_for_rollout": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Wait until node deployment changes are rolled out to all nodes, unless it is paused.",
+			},
+			"health": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"api_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the cluster Kubernetes API server.",
+			},
+			"actual_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Kubernetes version the control plane runs, version is a prefix of it.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the cluster was created, RFC 3339.",
+			},
+			"seed": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Seed datacenter hosting the cluster control plane.",
+			},
+			"resource_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version (ETag) of the cluster read, updates fail if the cluster was changed since.",
+			},
+			"nodedepl_resource_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version (ETag) of the node deployment read, updates fail if it was changed since.",
+			},
+			"create_stage": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Stage of create that failed after the cluster was created, the next apply resumes from it.",
+			},
+			"create_error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Error of the failed create stage.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Cluster type, e.g. kubernetes.",
+			},
 			"nodedepl": {
 				Type:     schema.TypeList,
 				Required: true,
@@ -117,6 +266,34 @@ func resourceCluster() *schema.Resource {
 								},
 							},
 						},
+						"paused": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Template changes are not rolled out to nodes while paused.",
+						},
+						"rollout_strategy": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"max_surge": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: validateIntOrPercentage,
+										Description:  "Number or percentage of nodes created above replicas during rollout.",
+									},
+									"max_unavailable": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: validateIntOrPercentage,
+										Description:  "Number or percentage of nodes unavailable during rollout.",
+									},
+								},
+							},
+						},
 						"flavor": {
 							Type:         schema.TypeString,
 							Required:     true,
@@ -132,6 +309,117 @@ func resourceCluster() *schema.Resource {
 							Optional: true,
 							Default:  true,
 						},
+						"disk_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Root volume size in GB, nodes use flavor's local disk if not set.",
+						},
+						"root_volume_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.NoZeroValues,
+							Description:  "Volume type of the root volume, requires disk_size.",
+						},
+						"availability_zone": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"node_labels": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"taints": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"key": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.NoZeroValues,
+									},
+									"value": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"effect": {
+										Type:     schema.TypeString,
+										Required: true,
+										ValidateFunc: validation.StringInSlice([]string{
+											gometakube.TaintEffectNoSchedule,
+											gometakube.TaintEffectPreferNoSchedule,
+											gometakube.TaintEffectNoExecute,
+										}, false),
+									},
+								},
+							},
+						},
+						"kubelet": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"max_pods": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										ValidateFunc: validation.IntAtLeast(1),
+									},
+									"eviction_hard": {
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Description: "Thresholds by signal, e.g. memory.available = \"100Mi\".",
+									},
+									"eviction_soft": {
+										Type:     schema.TypeMap,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"eviction_soft_grace_period": {
+										Type:     schema.TypeMap,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						"operating_system": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Computed:    true,
+							MaxItems:    1,
+							Description: "Changes roll nodes over to the new operating system, ubuntu if not set.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice(nodeOSNames, false),
+									},
+									"dist_upgrade_on_boot": {
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Default:     false,
+										Description: "ubuntu and centos only.",
+									},
+									"disable_auto_update": {
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Default:     false,
+										Description: "container_linux and flatcar only.",
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -140,194 +428,422 @@ func resourceCluster() *schema.Resource {
 }
 
 func resourceClusterCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gometakube.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutCreate)
+	defer cancel()
+	client := meta.(*metakubeProviderMeta).client
 	if minReplicas, maxReplicas, err := checkClusterAutoscaleValid(d); err != nil {
 		return err
-	} else if dc, err := getClusterDatacenter(client, d.Get("dc").(string)); err != nil {
+	} else if err := checkClusterNodedeplDisk(d); err != nil {
 		return err
-	} else if err := checkClusterTenantValid(client, dc, d); err != nil {
+	} else if creds, err := clusterOpenstackCredentials(d, meta.(*metakubeProviderMeta)); err != nil {
 		return err
-	} else if err := checkClusterNodedeplImage(client, dc, d); err != nil {
+	} else if dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string)); err != nil {
 		return err
-	} else if project, _, err := client.Projects.Get(context.Background(), d.Get("project_id").(string)); err != nil {
+	} else if err := checkClusterTenantValid(ctx, client, dc, creds, d); err != nil {
+		return err
+	} else if err := checkClusterNodedeplImage(ctx, client, dc, creds, d); err != nil {
+		return err
+	} else if project, _, err := client.Projects.Get(ctx, d.Get("project_id").(string)); err != nil {
 		return err
 	} else if err := checkClusterDoesNotRedefineProjectLabels(project, d); err != nil {
 		return err
-	} else if clusterVersion, err := getClusterVersionToUse(client, d.Get("version").(string)); err != nil {
+	} else if clusterVersion, err := getClusterVersionToUse(ctx, client, d.Get("version").(string)); err != nil {
 		return err
 	} else {
-		nodedepl := d.Get("nodedepl").([]interface{})[0].(map[string]interface{})
-		create := &gometakube.CreateClusterRequest{
-			Cluster: gometakube.Cluster{
-				Name:   d.Get("name").(string),
-				Labels: clusterLabelsMap(d),
-				Spec: &gometakube.ClusterSpec{
-					Version: clusterVersion,
-					AuditLogging: gometakube.ClusterSpecAuditLogging{
-						Enabled: d.Get("audit_logging").(bool),
-					},
-					Cloud: &gometakube.ClusterSpecCloud{
-						OpenStack: &gometakube.ClusterSpecCloudOpenstack{
-							Domain:         "Default",
-							Tenant:         d.Get("tenant").(string),
-							Username:       d.Get("provider_username").(string),
-							Password:       d.Get("provider_password").(string),
-							FloatingIPPool: "ext-net",
+		prj := d.Get("project_id").(string)
+		idempotencyKey := clusterIdempotencyKey(d)
+		obj, err := findClusterByIdempotencyKey(ctx, client, prj, d.Get("dc").(string), d.Get("name").(string), idempotencyKey)
+		if err != nil {
+			return err
+		}
+		if obj != nil {
+			nodedepls, _, err := client.NodeDeployments.List(ctx, prj, dc.Spec.Seed, obj.ID)
+			if err != nil {
+				return errors.Wrap(err, "list node deployments")
+			}
+			createdAfter := time.Now().Add(-d.Timeout(schema.TimeoutCreate))
+			if reason := clusterAdoptionConflict(obj, nodedepls, d.Get("tenant").(string), clusterVersion, d.Get("nodedepl.0.name").(string), createdAfter); reason != "" {
+				return errors.Errorf("cluster %s has the same project, datacenter and name, but %s: it may belong to another state or to a cluster being replaced, rename this cluster or run `terraform import` with ID `%s:%s:%s` to manage it", obj.ID, reason, prj, d.Get("dc").(string), obj.ID)
+			}
+			// Previous create was interrupted before cluster ID got to the state.
+			log.Printf("[INFO] adopting cluster %s created by interrupted apply, resuming create", obj.ID)
+		} else {
+			nodedepl := d.Get("nodedepl").([]interface{})[0].(map[string]interface{})
+			create := &gometakube.CreateClusterRequest{
+				Cluster: gometakube.Cluster{
+					Name:   d.Get("name").(string),
+					Labels: clusterLabelsWithIdempotencyKey(clusterLabelsMap(d), idempotencyKey),
+					Spec: &gometakube.ClusterSpec{
+						Version: clusterVersion,
+						AuditLogging: gometakube.ClusterSpecAuditLogging{
+							Enabled: d.Get("audit_logging").(bool),
 						},
-						DataCenter: d.Get("dc").(string),
-					},
-					MachineNetworks: []gometakube.ClusterSpecMachineNetwork{},
-				},
-				Type:    "kubernetes",
-				SSHKeys: []string{},
-			},
-			NodeDeployment: gometakube.NodeDeployment{
-				Name: nodedepl["name"].(string),
-				Spec: gometakube.NodeDeploymentSpec{
-					Template: gometakube.NodeDeploymentSpecTemplate{
-						Cloud: gometakube.NodeDeploymentSpecTemplateCloud{
-							Openstack: gometakube.NodeDeploymentSpecTemplateCloudOpenstack{
-								Flavor:        nodedepl["flavor"].(string),
-								Image:         nodedepl["image"].(string),
-								UseFloatingIP: nodedepl["use_floating_ip"].(bool),
+						Cloud: &gometakube.ClusterSpecCloud{
+							OpenStack: &gometakube.ClusterSpecCloudOpenstack{
+								Domain:                      creds.Domain,
+								Tenant:                      d.Get("tenant").(string),
+								Username:                    creds.Username,
+								Password:                    creds.Password,
+								ApplicationCredentialID:     creds.ApplicationCredentialID,
+								ApplicationCredentialSecret: creds.ApplicationCredentialSecret,
+								FloatingIPPool:              "ext-net",
 							},
+							DataCenter: d.Get("dc").(string),
 						},
-						OperatingSystem: gometakube.NodeDeploymentSpecTemplateOS{
-							Ubuntu: &gometakube.NodeDeploymentSpecTemplateOSOptions{
-								DistUpgradeOnBoot: new(bool),
+						MachineNetworks: []gometakube.ClusterSpecMachineNetwork{},
+					},
+					Type:    "kubernetes",
+					SSHKeys: []string{},
+				},
+				NodeDeployment: gometakube.NodeDeployment{
+					Name: nodedepl["name"].(string),
+					Spec: gometakube.NodeDeploymentSpec{
+						Template: gometakube.NodeDeploymentSpecTemplate{
+							Cloud: gometakube.NodeDeploymentSpecTemplateCloud{
+								Openstack: nodeDeploymentOpenstack(nodedepl, nil),
 							},
+							OperatingSystem: nodeDeploymentOS(nodedepl),
+							Labels:          nodeDeploymentLabels(nodedepl, nil),
+							Taints:          nodeDeploymentTaints(nodedepl),
+							Kubelet:         nodeDeploymentKubelet(nodedepl),
 						},
+						Replicas:    uint(nodedepl["replicas"].(int)),
+						MinReplicas: uint(minReplicas),
+						MaxReplicas: uint(maxReplicas),
+						Paused:      nodedepl["paused"].(bool),
+						Strategy:    nodeDeploymentStrategy(nodedepl),
 					},
-					Replicas:    uint(nodedepl["replicas"].(int)),
-					MinReplicas: uint(minReplicas),
-					MaxReplicas: uint(maxReplicas),
 				},
-			},
-		}
-		prj := d.Get("project_id").(string)
-		obj, _, err := client.Clusters.Create(context.Background(), prj, dc.Spec.Seed, create)
-		if err != nil {
-			return errors.Wrapf(err, "create cluster")
+			}
+			obj, _, err = client.Clusters.Create(ctx, prj, dc.Spec.Seed, create)
+			if err != nil {
+				return errors.Wrapf(err, "create cluster")
+			}
 		}
 		d.SetId(obj.ID)
-		if err := manageSSHKeysInCluster(client, nil, d.Get("sshkeys"), prj, dc.Spec.Seed, d.Id()); err != nil {
+		d.Set("openstack_credentials_hash", openstackCredentialsHash(obj.ID, creds))
+		return runClusterCreateStages(ctx, d, client, prj, dc.Spec.Seed, "")
+	}
+}
+
+// runClusterCreateStages runs stages of create following cluster creation, starting with stage,
+// from the first one if stage is empty. Stage being run is recorded in create_stage.
+// Create does not fail when a stage fails, failed create would taint the cluster and the next apply would replace it.
+// Instead the failed stage and its error are kept in state, the next plan shows them and the apply resumes from the stage.
+func runClusterCreateStages(ctx context.Context, d *schema.ResourceData, client *gometakube.Client, prj, dc, stage string) error {
+	stages := []struct {
+		name string
+		run  func() error
+	}{
+		{"assign ssh keys", func() error {
+			return manageSSHKeysInCluster(ctx, client, nil, d.Get("sshkeys"), prj, dc, d.Id())
+		}},
+		{"wait cluster is healthy", func() error {
+			return waitForClusterHealthy(ctx, client, prj, dc, d.Id())
+		}},
+		{"wait node deployment is created", func() error {
+			return waitNodeDeploymentCreate(ctx, client, prj, dc, d.Id(), d.Get("nodedepl.0.name").(string))
+		}},
+		{"wait node deployment rollout", func() error {
+			return waitForClusterNodeDeploymentRollout(ctx, d, client, prj, dc, 1)
+		}},
+	}
+	started := stage == ""
+	for _, s := range stages {
+		if s.name == stage {
+			started = true
+		}
+		if !started {
+			continue
+		}
+		d.Set("create_stage", s.name)
+		d.SetPartial("create_stage")
+		if err := s.run(); err != nil {
+			err = clusterCreateStageError(d, s.name, err)
+			d.Set("create_error", err.Error())
+			d.SetPartial("create_error")
+			if d.IsNewResource() {
+				log.Printf("[ERROR] %v", err)
+				return nil
+			}
 			return err
 		}
-		return waitForClusterRunningAndNodeDeploymentCreate(client, prj, dc.Spec.Seed, obj.ID, d.Get("nodedepl.0.name").(string))
 	}
+	d.Set("create_stage", "")
+	d.Set("create_error", "")
+	d.SetPartial("create_stage")
+	d.SetPartial("create_error")
+	return nil
+}
+
+// clusterCreateStageError reports stage of create which failed after cluster was created.
+func clusterCreateStageError(d *schema.ResourceData, stage string, err error) error {
+	return errors.Wrapf(err, "cluster %s is created, but stage `%s` failed, next apply resumes from it", d.Id(), stage)
+}
+
+// resumeClusterCreateDiff plans an update of a cluster which create failed at some stage, the update resumes create.
+// The plan shows the failed stage and its error being cleared.
+func resumeClusterCreateDiff(d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || d.Get("create_stage").(string) == "" {
+		return nil
+	}
+	if err := d.SetNew("create_stage", ""); err != nil {
+		return err
+	}
+	return d.SetNew("create_error", "")
+}
+
+// resourceClusterImport imports cluster by project_id:dc:cluster_id.
+// Tenant and node deployment name are taken from the cluster, it must have exactly one node deployment.
+func resourceClusterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+	client := meta.(*metakubeProviderMeta).client
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 3 {
+		return nil, errors.Errorf("unexpected ID format `%s`, want project_id:dc:cluster_id", d.Id())
+	}
+	prj, id := parts[0], parts[2]
+	dc, err := getClusterDatacenter(ctx, client, parts[1])
+	if err != nil {
+		return nil, err
+	}
+	obj, err := getCluster(ctx, client, prj, dc.Spec.Seed, id)
+	if err != nil {
+		return nil, err
+	}
+	nodedepls, _, err := client.NodeDeployments.List(ctx, prj, dc.Spec.Seed, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "list node deployments")
+	}
+	if len(nodedepls) != 1 {
+		return nil, errors.Errorf("cluster %s has %d node deployments, want exactly one", id, len(nodedepls))
+	}
+	d.SetId(id)
+	d.Set("project_id", prj)
+	d.Set("dc", parts[1])
+	if obj.Spec != nil && obj.Spec.Cloud != nil && obj.Spec.Cloud.OpenStack != nil {
+		d.Set("tenant", obj.Spec.Cloud.OpenStack.Tenant)
+	}
+	d.Set("nodedepl", []interface{}{map[string]interface{}{"name": nodedepls[0].Name}})
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceClusterRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gometakube.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+	client := meta.(*metakubeProviderMeta).client
 	id := d.Id()
 	projectID := d.Get("project_id").(string)
-	if dc, err := getClusterDatacenter(client, d.Get("dc").(string)); err != nil {
-		return err
-	} else if obj, err := getCluster(client, projectID, dc.Spec.Seed, id); err != nil {
+	if dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string)); err != nil {
 		return err
+	} else if obj, resp, err := client.Clusters.Get(ctx, projectID, dc.Spec.Seed, id); err != nil {
+		return errors.Wrap(err, "get cluster")
 	} else if obj == nil || obj.DeletionTimestamp != nil {
 		// Cluster was deleted
 		d.SetId("")
 		return nil
-	} else if nodeDeployment, err := getClusterNodeDeployment(client, projectID, dc.Spec.Seed, id, d.Get("nodedepl.0.name").(string)); err != nil {
+	} else if found, err := findClusterNodeDeployment(ctx, client, projectID, dc.Spec.Seed, id, d.Get("nodedepl.0.name").(string)); err != nil {
 		return err
-	} else if project, _, err := client.Projects.Get(context.Background(), projectID); err != nil {
+	} else if found == nil && d.Get("create_stage").(string) == "" {
+		return errors.Errorf("find node deployment by name `%s`", d.Get("nodedepl.0.name").(string))
+	} else if project, _, err := client.Projects.Get(ctx, projectID); err != nil {
 		return err
-	} else if sshkeys, _, err := client.SSHKeys.ListAssigned(context.Background(), projectID, dc.Spec.Seed, id); err != nil {
+	} else if sshkeys, _, err := client.SSHKeys.ListAssigned(ctx, projectID, dc.Spec.Seed, id); err != nil {
 		return errors.Wrap(err, "list sshkeys")
 	} else {
-		d.Set("name", obj.Name)
-		labelsToSet := obj.Labels
-		for k := range project.Labels {
-			delete(labelsToSet, k)
+		// Health is informational, it is left unset rather than failing refresh.
+		if health, _, err := client.Clusters.Health(ctx, projectID, dc.Spec.Seed, id); err != nil {
+			log.Printf("[WARN] get cluster %s health: %v", id, err)
+			d.Set("health", nil)
+		} else {
+			d.Set("health", clusterHealthMap(health))
 		}
-		d.Set("labels", labelsToSet)
+		d.Set("name", obj.Name)
+		labels, inherited, effective := clusterLabels(obj.Labels, project.Labels, d.Get("override_project_labels").(*schema.Set))
+		d.Set("labels", labels)
+		d.Set("inherited_labels", inherited)
+		d.Set("effective_labels", effective)
 		version := d.Get("version").(string)
 		if obj.Spec.Version[:len(version)] != version {
 			d.Set("version", obj.Spec.Version)
 		}
 		d.Set("dc", obj.Spec.Cloud.DataCenter)
 		d.Set("audit_logging", obj.Spec.AuditLogging.Enabled)
+		d.Set("seed", dc.Spec.Seed)
+		d.Set("type", obj.Type)
+		d.Set("actual_version", obj.Spec.Version)
+		if obj.Status != nil {
+			d.Set("api_url", obj.Status.URL)
+			if obj.Status.Version != "" {
+				d.Set("actual_version", obj.Status.Version)
+			}
+		}
+		if obj.CreationTimestamp != nil {
+			d.Set("created_at", obj.CreationTimestamp.Format(time.RFC3339))
+		}
 
-		d.Set("nodedepl", nodeDeploymentUpdatesMap(nodeDeployment))
-
-		keynames := make([]string, 0)
-		for _, key := range sshkeys {
-			keynames = append(keynames, key.Name)
+		d.Set("resource_version", gometakube.ETag(resp))
+		if d.Get("openstack_credentials_hash").(string) == "" {
+			// Imported cluster or state from before the hash was kept, credentials configured now are assumed applied.
+			if creds, err := clusterOpenstackCredentials(d, meta.(*metakubeProviderMeta)); err == nil && creds != nil {
+				d.Set("openstack_credentials_hash", openstackCredentialsHash(id, creds))
+			}
+		}
+		// Node deployment of a cluster which create failed may not exist yet, it is kept as configured.
+		if found != nil {
+			nodeDeployment, nodeDeploymentResp, err := client.NodeDeployments.Get(ctx, projectID, dc.Spec.Seed, id, found.ID)
+			if err != nil {
+				return errors.Wrap(err, "get node deployment")
+			}
+			d.Set("nodedepl", nodeDeploymentUpdatesMap(nodeDeployment))
+			d.Set("nodedepl_resource_version", gometakube.ETag(nodeDeploymentResp))
 		}
-		d.Set("sshkeys", keynames)
+
+		d.Set("sshkeys", clusterManagedSSHKeys(d.Get("sshkeys").(*schema.Set), sshkeys))
 		return nil
 	}
 }
 
 func resourceClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutUpdate)
+	defer cancel()
 	d.Partial(true)
 	defer d.Partial(false)
-	client := meta.(*gometakube.Client)
+	client := meta.(*metakubeProviderMeta).client
 	projectID := d.Get("project_id").(string)
-	dc, err := getClusterDatacenter(client, d.Get("dc").(string))
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
 	if err != nil {
 		return err
 	}
-	if d.HasChanges("name", "labels", "audit_logging") {
-		if cluster, err := getCluster(client, projectID, dc.Spec.Seed, d.Id()); err != nil {
+	if stage, _ := d.GetChange("create_stage"); stage.(string) != "" {
+		if err := runClusterCreateStages(ctx, d, client, projectID, dc.Spec.Seed, stage.(string)); err != nil {
 			return err
-		} else if cluster == nil {
+		}
+	}
+	// Patchable cluster fields as they are on server after each patch made by this update.
+	clusterRead := clusterStatePatchRequest(d)
+	if d.HasChanges("name", "labels", "audit_logging") {
+		if cluster, resp, err := client.Clusters.Get(ctx, projectID, dc.Spec.Seed, d.Id()); err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return errors.Wrap(err, "get cluster")
+		} else if err != nil || cluster.DeletionTimestamp != nil {
 			// Cluster was deleted
 			d.SetId("")
 			return nil
-		} else if project, _, err := client.Projects.Get(context.Background(), d.Get("project_id").(string)); err != nil {
+		} else if project, _, err := client.Projects.Get(ctx, d.Get("project_id").(string)); err != nil {
 			return err
 		} else if err := checkClusterDoesNotRedefineProjectLabels(project, d); err != nil {
 			return err
 		} else {
-			patch := &gometakube.PatchClusterRequest{
+			from := clusterPatchRequest(cluster)
+			oldLabels, _ := d.GetChange("labels")
+			to := &gometakube.PatchClusterRequest{
 				Name:   d.Get("name").(string),
-				Labels: clusterLabelsMap(d),
+				Labels: clusterLabelsToPatch(cluster.Labels, oldLabels.(map[string]interface{}), clusterLabelsMap(d)),
 				Spec: &gometakube.PatchClusterRequestSpec{
 					AuditLogging: &gometakube.ClusterSpecAuditLogging{
 						Enabled: d.Get("audit_logging").(bool),
 					},
 				},
 			}
-			_, _, err = client.Clusters.Patch(context.Background(), projectID, dc.Spec.Seed, d.Id(), patch)
+			var patched *gometakube.Cluster
+			var patchResp *http.Response
+			err = updateIfUnchanged(ctx, "cluster", d.Get("resource_version").(string), clusterRead, func() (interface{}, string, error) {
+				// Patch is computed from cluster as it is now, so retry is sent with its etag.
+				return clusterCurrentPatchRequest(cluster), gometakube.ETag(resp), nil
+			}, func(ctx context.Context) (err error) {
+				patched, patchResp, err = client.Clusters.MergePatch(ctx, projectID, dc.Spec.Seed, d.Id(), from, to)
+				return err
+			})
 			if err != nil {
 				return errors.Wrap(err, "patch cluster (is cluster provisioning compete?)")
 			}
+			clusterRead = clusterCurrentPatchRequest(patched)
+			d.Set("resource_version", gometakube.ETag(patchResp))
 			d.SetPartial("name")
 			d.SetPartial("labels")
 			d.SetPartial("audit_logging")
+			d.SetPartial("resource_version")
 		}
 	}
+	if d.HasChanges("provider_username", "provider_password", "application_credential_id", "application_credential_secret", "openstack_credentials_hash") {
+		creds, err := clusterOpenstackCredentials(d, meta.(*metakubeProviderMeta))
+		if err != nil {
+			return err
+		}
+		// Merge patch from previous credentials clears fields not used anymore,
+		// e.g. username and password when switching to application credential.
+		from := clusterOpenstackCredentialsPatch(clusterPreviousOpenstackCredentials(d, creds))
+		_, err = patchClusterIfUnchanged(ctx, d, client, projectID, dc.Spec.Seed, clusterRead, func(ctx context.Context) (*gometakube.Cluster, *http.Response, error) {
+			return client.Clusters.MergePatch(ctx, projectID, dc.Spec.Seed, d.Id(), from, clusterOpenstackCredentialsPatch(creds))
+		})
+		if err != nil {
+			return errors.Wrap(err, "rotate openstack credentials")
+		}
+		d.SetPartial("provider_username")
+		d.SetPartial("provider_password")
+		d.SetPartial("application_credential_id")
+		d.SetPartial("application_credential_secret")
+		d.Set("openstack_credentials_hash", openstackCredentialsHash(d.Id(), creds))
+		d.SetPartial("openstack_credentials_hash")
+	}
 	if d.HasChange("nodedepl") {
 		if minReplicas, maxReplicas, err := checkClusterAutoscaleValid(d); err != nil {
 			return err
-		} else if nodedepl, err := getClusterNodeDeployment(client, projectID, dc.Spec.Seed, d.Id(), d.Get("nodedepl.0.name").(string)); err != nil {
+		} else if err := checkClusterNodedeplDisk(d); err != nil {
+			return err
+		} else if creds, err := clusterOpenstackCredentials(d, meta.(*metakubeProviderMeta)); err != nil {
+			return err
+		} else if found, err := getClusterNodeDeployment(ctx, client, projectID, dc.Spec.Seed, d.Id(), d.Get("nodedepl.0.name").(string)); err != nil {
 			return err
-		} else if err := checkClusterNodedeplImage(client, dc, d); err != nil {
+		} else if nodedepl, resp, err := client.NodeDeployments.Get(ctx, projectID, dc.Spec.Seed, d.Id(), found.ID); err != nil {
+			return errors.Wrap(err, "get node deployment")
+		} else if err := checkClusterNodedeplImage(ctx, client, dc, creds, d); err != nil {
 			return err
 		} else {
 			patch := &gometakube.NodeDeploymentsPatchRequest{Spec: nodedepl.Spec}
 			patch.Spec.Replicas = uint(d.Get("nodedepl.0.replicas").(int))
 			patch.Spec.MinReplicas = uint(minReplicas)
 			patch.Spec.MaxReplicas = uint(maxReplicas)
-			patch.Spec.Template.Cloud.Openstack.Flavor = d.Get("nodedepl.0.flavor").(string)
-			patch.Spec.Template.Cloud.Openstack.Image = d.Get("nodedepl.0.image").(string)
-			patch.Spec.Template.Cloud.Openstack.UseFloatingIP = d.Get("nodedepl.0.use_floating_ip").(bool)
-			_, _, err = client.NodeDeployments.Patch(context.Background(), projectID, dc.Spec.Seed, d.Id(), nodedepl.ID, patch)
+			v := d.Get("nodedepl").([]interface{})[0].(map[string]interface{})
+			patch.Spec.Template.Cloud.Openstack = nodeDeploymentOpenstack(v, &nodedepl.Spec.Template.Cloud.Openstack)
+			patch.Spec.Paused = v["paused"].(bool)
+			patch.Spec.Strategy = nodeDeploymentStrategy(v)
+			patch.Spec.Template.OperatingSystem = nodeDeploymentOS(v)
+			patch.Spec.Template.Labels = nodeDeploymentLabels(v, nodedepl.Spec.Template.Labels)
+			patch.Spec.Template.Taints = nodeDeploymentTaints(v)
+			patch.Spec.Template.Kubelet = nodeDeploymentKubelet(v)
+			from := &gometakube.NodeDeploymentsPatchRequest{Spec: nodedepl.Spec}
+			var patched *gometakube.NodeDeployment
+			var patchResp *http.Response
+			read, _ := d.GetChange("nodedepl")
+			err = updateIfUnchanged(ctx, "node deployment", d.Get("nodedepl_resource_version").(string), firstListItem(read), func() (interface{}, string, error) {
+				// Patch is computed from node deployment as it is now, so retry is sent with its etag.
+				return firstListItem(nodeDeploymentStateList(nodedepl)), gometakube.ETag(resp), nil
+			}, func(ctx context.Context) (err error) {
+				patched, patchResp, err = client.NodeDeployments.MergePatch(ctx, projectID, dc.Spec.Seed, d.Id(), nodedepl.ID, from, patch)
+				return err
+			})
 			if err != nil {
 				return errors.Wrapf(err, "patch node deployment")
 			}
+			d.Set("nodedepl_resource_version", gometakube.ETag(patchResp))
 			d.SetPartial("nodedepl")
+			d.SetPartial("nodedepl_resource_version")
+			if minGeneration := nodeDeploymentRolloutGeneration(nodedepl, patched); minGeneration != 0 {
+				if err := waitForClusterNodeDeploymentRollout(ctx, d, client, projectID, dc.Spec.Seed, minGeneration); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	if d.HasChange("version") {
 		versionPrefix := d.Get("version").(string)
-		if cluster, _, err := client.Clusters.Get(context.Background(), projectID, dc.Spec.Seed, d.Id()); err != nil {
+		if cluster, _, err := client.Clusters.Get(ctx, projectID, dc.Spec.Seed, d.Id()); err != nil {
 			return err
 		} else if clusterVersionsHasPrefix(cluster.Spec.Version, versionPrefix) {
 			return nil
-		} else if versionToUse, err := getClusterVersionToUse(client, versionPrefix); err != nil {
+		} else if versionToUse, err := getClusterVersionToUse(ctx, client, versionPrefix); err != nil {
 			return err
 		} else if invalidUpgrade, err := clusterVersionBigger(cluster.Spec.Version, versionToUse); err != nil {
 			return nil
@@ -336,7 +852,7 @@ func resourceClusterUpdate(d *schema.ResourceData, meta interface{}) error {
 		} else {
 			// Upgrade cluster continuously to desired version.
 			for {
-				version, err := getClusterVersionToUpgradeInto(client, projectID, dc.Spec.Seed, d.Id())
+				version, err := getClusterVersionToUpgradeInto(ctx, client, projectID, dc.Spec.Seed, d.Id())
 				if version == "" {
 					if clusterVersionsHasPrefix(cluster.Spec.Version, versionPrefix) {
 						break
@@ -348,28 +864,44 @@ func resourceClusterUpdate(d *schema.ResourceData, meta interface{}) error {
 						Version: version,
 					},
 				}
-				cluster, _, err = client.Clusters.Patch(context.Background(), projectID, dc.Spec.Seed, d.Id(), patch)
+				cluster, err = patchClusterIfUnchanged(ctx, d, client, projectID, dc.Spec.Seed, clusterRead, func(ctx context.Context) (*gometakube.Cluster, *http.Response, error) {
+					return client.Clusters.Patch(ctx, projectID, dc.Spec.Seed, d.Id(), patch)
+				})
 				if err != nil {
 					return errors.Wrap(err, "patch cluster (is cluster provisioning compete?)")
 				}
-				if err := waitForClusterHealthy(client, projectID, dc.Spec.Seed, d.Id()); err != nil {
+				clusterRead = clusterCurrentPatchRequest(cluster)
+				if err := waitForClusterHealthy(ctx, client, projectID, dc.Spec.Seed, d.Id()); err != nil {
 					return err
 				}
 				if cluster.Spec.Version == versionToUse {
 					break
 				}
 			}
-			_, err = client.NodeDeployments.Upgrade(context.Background(), projectID, dc.Spec.Seed, d.Id(), &gometakube.UpgradeNodesRequest{
+			nodedepl, err := getClusterNodeDeployment(ctx, client, projectID, dc.Spec.Seed, d.Id(), d.Get("nodedepl.0.name").(string))
+			if err != nil {
+				return err
+			}
+			_, err = client.NodeDeployments.Upgrade(ctx, projectID, dc.Spec.Seed, d.Id(), &gometakube.UpgradeNodesRequest{
 				Version: cluster.Spec.Version,
 			})
 			if err != nil {
 				return errors.Wrap(err, "upgrade node deployments")
 			}
+			upgraded, _, err := client.NodeDeployments.Get(ctx, projectID, dc.Spec.Seed, d.Id(), nodedepl.ID)
+			if err != nil {
+				return errors.Wrap(err, "get node deployment")
+			}
+			if minGeneration := nodeDeploymentRolloutGeneration(nodedepl, upgraded); minGeneration != 0 {
+				if err := waitForClusterNodeDeploymentRollout(ctx, d, client, projectID, dc.Spec.Seed, minGeneration); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	if d.HasChange("sshkeys") {
 		old, new := d.GetChange("sshkeys")
-		if err := manageSSHKeysInCluster(client, old, new, projectID, dc.Spec.Seed, d.Id()); err != nil {
+		if err := manageSSHKeysInCluster(ctx, client, old, new, projectID, dc.Spec.Seed, d.Id()); err != nil {
 			return err
 		}
 	}
@@ -377,131 +909,298 @@ func resourceClusterUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceClusterDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gometakube.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+	if err := checkDeletionProtection(d, "cluster"); err != nil {
+		return err
+	}
+	client := meta.(*metakubeProviderMeta).client
 	id := d.Id()
 	project := d.Get("project_id").(string)
-	if dc, err := getClusterDatacenter(client, d.Get("dc").(string)); err != nil {
+	if dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string)); err != nil {
 		return err
-	} else if _, err := client.Clusters.Delete(context.Background(), project, dc.Spec.Seed, id); err != nil {
+	} else if _, err := client.Clusters.DeleteWithCleanup(ctx, project, dc.Spec.Seed, id, &gometakube.ClusterDeleteOptions{
+		DeleteVolumes:       d.Get("delete_volumes_on_destroy").(bool),
+		DeleteLoadBalancers: d.Get("delete_load_balancers_on_destroy").(bool),
+	}); err != nil {
 		return errors.Wrap(err, "delete cluster")
 	} else {
-		return waitForClusterDelete(client, project, dc.Spec.Seed, id)
+		return waitForClusterDelete(ctx, client, project, dc.Spec.Seed, id)
 	}
 }
 
-func manageSSHKeysInCluster(client *gometakube.Client, old, new interface{}, prj, dc, cls string) error {
-	allKeys, _, err := client.SSHKeys.List(context.Background(), prj)
+func manageSSHKeysInCluster(ctx context.Context, client *gometakube.Client, old, new interface{}, prj, dc, cls string) error {
+	allKeys, _, err := client.SSHKeys.List(ctx, prj)
+	if err != nil {
+		return errors.Wrap(err, "list cluster sshkeys")
+	}
+	assignedKeys, _, err := client.SSHKeys.ListAssigned(ctx, prj, dc, cls)
 	if err != nil {
 		return errors.Wrap(err, "list cluster sshkeys")
 	}
-	actions := make(map[string]bool)
+	assigned := make(map[string]bool)
+	for _, key := range assignedKeys {
+		assigned[key.ID] = true
+	}
+	unassign, assign, err := clusterSSHKeyChanges(allKeys, old, new)
+	if err != nil {
+		return err
+	}
+	for _, id := range unassign {
+		if !assigned[id] {
+			continue
+		}
+		_, err = client.SSHKeys.RemoveFromCluster(ctx, prj, dc, cls, id)
+		if err != nil {
+			return errors.Wrap(err, "evict sshkey from cluster")
+		}
+	}
+	for _, id := range assign {
+		if assigned[id] {
+			continue
+		}
+		_, _, err = client.SSHKeys.AssignToCluster(ctx, prj, dc, cls, id)
+		if err != nil {
+			return errors.Wrap(err, "assign sshkey to cluster")
+		}
+	}
+	return nil
+}
+
+// clusterSSHKeyChanges resolves old and new keys, IDs or names, to key IDs
+// and returns IDs to unassign, old minus new, and IDs to assign, new minus old.
+// Old keys deleted from the project are skipped, there is nothing to unassign.
+func clusterSSHKeyChanges(keys []gometakube.SSHKey, old, new interface{}) (unassign, assign []string, err error) {
+	oldIDs := make(map[string]bool)
 	if old != nil {
 		for _, v := range old.(*schema.Set).List() {
-			actions[v.(string)] = false
+			if !sshKeyExists(keys, v.(string)) {
+				continue
+			}
+			id, err := findSSHKeyID(keys, v.(string))
+			if err != nil {
+				return nil, nil, err
+			}
+			oldIDs[id] = true
 		}
 	}
+	newIDs := make(map[string]bool)
 	if new != nil {
 		for _, v := range new.(*schema.Set).List() {
-			actions[v.(string)] = true
+			id, err := findSSHKeyID(keys, v.(string))
+			if err != nil {
+				return nil, nil, err
+			}
+			newIDs[id] = true
 		}
 	}
-	for v, action := range actions {
-		// action is true -> assign, action is false -> unassign.
-		var id string
-		for _, key := range allKeys {
-			if key.Name == v {
-				id = key.ID
-			}
+	for id := range oldIDs {
+		if !newIDs[id] {
+			unassign = append(unassign, id)
 		}
-		if action {
-			if id == "" {
-				return errors.Errorf("no ssh key named `%s`", v)
-			}
-			_, _, err = client.SSHKeys.AssignToCluster(context.Background(), prj, dc, cls, id)
-			if err != nil {
-				return errors.Wrap(err, "assign sshkey to cluster")
-			}
-		} else if id != "" {
-			_, err = client.SSHKeys.RemoveFromCluster(context.Background(), prj, dc, cls, id)
-			if err != nil {
-				return errors.Wrap(err, "evict sshkey from cluster")
+	}
+	for id := range newIDs {
+		if !oldIDs[id] {
+			assign = append(assign, id)
+		}
+	}
+	sort.Strings(unassign)
+	sort.Strings(assign)
+	return unassign, assign, nil
+}
+
+// sshKeyExists returns true if there is a key with id or name v.
+func sshKeyExists(keys []gometakube.SSHKey, v string) bool {
+	for _, key := range keys {
+		if key.ID == v || key.Name == v {
+			return true
+		}
+	}
+	return false
+}
+
+// findSSHKeyID returns id of a key with id or name v.
+// Lookup by name is deprecated, names are not unique and may change.
+func findSSHKeyID(keys []gometakube.SSHKey, v string) (string, error) {
+	var ids []string
+	for _, key := range keys {
+		if key.ID == v {
+			return key.ID, nil
+		}
+		if key.Name == v {
+			ids = append(ids, key.ID)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", errors.Errorf("no ssh key with ID or name `%s`", v)
+	case 1:
+		log.Printf("[WARN] ssh key `%s` referenced by name, names are deprecated, use ID `%s` instead", v, ids[0])
+		return ids[0], nil
+	default:
+		return "", errors.Errorf("ssh key name `%s` is ambiguous, use one of IDs: %s", v, strings.Join(ids, ", "))
+	}
+}
+
+// clusterManagedSSHKeys returns keys of configured that are still assigned, keeping configured form, ID or name.
+// Keys assigned by other means, e.g. attachment resources, are left out.
+func clusterManagedSSHKeys(configured *schema.Set, assigned []gometakube.SSHKey) []string {
+	ret := make([]string, 0)
+	for _, v := range configured.List() {
+		for _, key := range assigned {
+			if key.ID == v.(string) || key.Name == v.(string) {
+				ret = append(ret, v.(string))
+				break
 			}
 		}
 	}
-	return nil
+	return ret
 }
 
-func waitForClusterDelete(client *gometakube.Client, prj, dc, id string) error {
+func waitForClusterDelete(ctx context.Context, client *gometakube.Client, prj, dc, id string) error {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
-	timeout := 10 * 60
-	n := 0
-	for range ticker.C {
-		_, resp, err := client.Clusters.Get(context.Background(), prj, dc, id)
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "wait cluster delete")
+		case <-ticker.C:
+		}
+		_, resp, err := client.Clusters.Get(ctx, prj, dc, id)
 		if err != nil {
 			if resp != nil && resp.StatusCode == http.StatusNotFound {
 				return nil
 			}
 			return errors.Wrapf(err, "GET cluster")
 		}
-		if n > timeout {
-			return errors.New("cluster delete timeout")
-		}
-		n++
 	}
-	return nil
 }
 
-func waitForClusterRunningAndNodeDeploymentCreate(client *gometakube.Client, prj, dc, cls, nodedepl string) error {
-	if err := waitForClusterHealthy(client, prj, dc, cls); err != nil {
-		return err
+func waitForClusterHealthy(ctx context.Context, client *gometakube.Client, prj, dc, id string) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var last *gometakube.ClusterHealth
+	for {
+		select {
+		case <-ctx.Done():
+			notReady := "health unknown"
+			if last != nil {
+				notReady = "not ready: " + strings.Join(last.NotReady(), ", ")
+			}
+			return clusterWaitError(ctx, client, prj, dc, id, "wait cluster is up, "+notReady)
+		case <-ticker.C:
+		}
+		h, _, err := client.Clusters.Health(ctx, prj, dc, id)
+		if err == nil {
+			last = h
+		}
+		if last != nil && last.Healthy() {
+			return nil
+		}
 	}
-	return waitNodeDeploymentCreate(client, prj, dc, cls, nodedepl)
 }
 
-func waitForClusterHealthy(client *gometakube.Client, prj, dc, id string) error {
+func waitNodeDeploymentCreate(ctx context.Context, client *gometakube.Client, prj, dc, cls, name string) error {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
-	timeout := 10 * 60
-	n := 0
-	for range ticker.C {
-		h, _, _ := client.Clusters.Health(context.Background(), prj, dc, id)
-		if h != nil && h.Healthy() {
-			return nil
+	for {
+		select {
+		case <-ctx.Done():
+			return clusterWaitError(ctx, client, prj, dc, cls, "create node deployment")
+		case <-ticker.C:
 		}
-		if n > timeout {
-			return errors.New("wait cluster is up timeout")
+		if _, err := getClusterNodeDeployment(ctx, client, prj, dc, cls, name); err == nil {
+			return nil
 		}
-		n++
 	}
-	return nil
 }
 
-func waitNodeDeploymentCreate(client *gometakube.Client, prj, dc, cls, name string) (err error) {
-	ticker := time.NewTicker(time.Second)
+// waitForClusterNodeDeploymentRollout waits for rollout of node deployment if enabled and it is not paused.
+func waitForClusterNodeDeploymentRollout(ctx context.Context, d *schema.ResourceData, client *gometakube.Client, prj, dc string, minGeneration uint) error {
+	if !d.Get("wait_for_rollout").(bool) || d.Get("nodedepl.0.paused").(bool) {
+		return nil
+	}
+	return waitNodeDeploymentRollout(ctx, client, prj, dc, d.Id(), d.Get("nodedepl.0.name").(string), minGeneration)
+}
+
+// waitNodeDeploymentRollout waits until controller observed generation minGeneration
+// and all replicas are updated, ready and available.
+func waitNodeDeploymentRollout(ctx context.Context, client *gometakube.Client, prj, dc, cls, name string, minGeneration uint) error {
+	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
-	timeout := 5 * 60
-	n := 0
-	for range ticker.C {
-		_, err = getClusterNodeDeployment(client, prj, dc, cls, name)
-		if err == nil {
-			return nil
+	var last *gometakube.NodeDeployment
+	for {
+		select {
+		case <-ctx.Done():
+			progress := "status unknown"
+			if last != nil {
+				progress = nodeDeploymentRolloutProgress(last, minGeneration)
+			}
+			return clusterWaitError(ctx, client, prj, dc, cls, "wait node deployment rollout, "+progress)
+		case <-ticker.C:
 		}
-		if n > timeout {
-			if err != nil {
-				return err
+		nodedepl, err := getClusterNodeDeployment(ctx, client, prj, dc, cls, name)
+		if err == nil {
+			last = nodedepl
+			if nodeDeploymentRolledOut(nodedepl, minGeneration) {
+				return nil
 			}
-			return errors.New("create node deployment timeout")
 		}
-		n++
 	}
-	return err
 }
 
-func checkClusterNodedeplImage(client *gometakube.Client, dc *gometakube.Datacenter, d *schema.ResourceData) error {
-	providerUsername := d.Get("provider_username").(string)
-	providerPassword := d.Get("provider_password").(string)
-	images, _, err := client.Openstack.Images(context.Background(), dc.Metadata.Name, "Default", providerUsername, providerPassword)
+func nodeDeploymentRolledOut(nodedepl *gometakube.NodeDeployment, minGeneration uint) bool {
+	status, replicas := nodedepl.Status, nodedepl.Spec.Replicas
+	return status != nil &&
+		status.ObservedGeneration >= minGeneration &&
+		status.ObservedGeneration >= nodedepl.Generation &&
+		status.Replicas == replicas &&
+		status.UpdatedReplicas == replicas &&
+		status.ReadyReplicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+// nodeDeploymentRolloutGeneration returns generation which rollout is awaited after node deployment
+// was changed from before to after, zero if there is nothing to roll out. Generation is increased only
+// when spec changes. If api does not report it, rollout is awaited when spec changed,
+// until the controller observes a generation newer than before.
+func nodeDeploymentRolloutGeneration(before, after *gometakube.NodeDeployment) uint {
+	if after.Generation != 0 {
+		if after.Generation > before.Generation {
+			return after.Generation
+		}
+		return 0
+	}
+	if reflect.DeepEqual(before.Spec, after.Spec) {
+		return 0
+	}
+	observed := uint(0)
+	if before.Status != nil {
+		observed = before.Status.ObservedGeneration
+	}
+	log.Printf("[WARN] node deployment %s generation is not reported, waiting for observed generation %d", after.Name, observed+1)
+	return observed + 1
+}
+
+func nodeDeploymentRolloutProgress(nodedepl *gometakube.NodeDeployment, minGeneration uint) string {
+	status := nodedepl.Status
+	if status == nil {
+		return "no status reported"
+	}
+	replicas := nodedepl.Spec.Replicas
+	if nodedepl.Generation > minGeneration {
+		minGeneration = nodedepl.Generation
+	}
+	return fmt.Sprintf("observed generation %d of %d, replicas %d, updated %d/%d, ready %d/%d, available %d/%d",
+		status.ObservedGeneration, minGeneration,
+		status.Replicas,
+		status.UpdatedReplicas, replicas,
+		status.ReadyReplicas, replicas,
+		status.AvailableReplicas, replicas)
+}
+
+func checkClusterNodedeplImage(ctx context.Context, client *gometakube.Client, dc *gometakube.Datacenter, creds *gometakube.OpenstackCredentials, d *schema.ResourceData) error {
+	images, _, err := client.Openstack.Images(ctx, dc.Metadata.Name, creds)
 	if err != nil {
 		return errors.Wrap(err, "list images")
 	}
@@ -509,7 +1208,7 @@ func checkClusterNodedeplImage(client *gometakube.Client, dc *gometakube.Datacen
 	imageName := nodedepl["image"].(string)
 	for _, image := range images {
 		if image.Name == imageName {
-			return nil
+			return checkNodeDeploymentOS(nodedepl, &image)
 		}
 	}
 	availableImages := make([]string, 0)
@@ -522,10 +1221,8 @@ func checkClusterNodedeplImage(client *gometakube.Client, dc *gometakube.Datacen
 		strings.Join(availableImages, "\n"))
 }
 
-func checkClusterTenantValid(client *gometakube.Client, dc *gometakube.Datacenter, d *schema.ResourceData) error {
-	providerUsername := d.Get("provider_username").(string)
-	providerPassword := d.Get("provider_password").(string)
-	tenants, _, err := client.Openstack.Tenants(context.Background(), dc.Metadata.Name, "Default", providerUsername, providerPassword)
+func checkClusterTenantValid(ctx context.Context, client *gometakube.Client, dc *gometakube.Datacenter, creds *gometakube.OpenstackCredentials, d *schema.ResourceData) error {
+	tenants, _, err := client.Openstack.Tenants(ctx, dc.Metadata.Name, creds)
 	if err != nil {
 		return errors.Wrap(err, "list tenants")
 	}
@@ -547,24 +1244,60 @@ func checkClusterTenantValid(client *gometakube.Client, dc *gometakube.Datacente
 
 func checkClusterDoesNotRedefineProjectLabels(project *gometakube.Project, d *schema.ResourceData) error {
 	clusterLabels := d.Get("labels").(map[string]interface{})
+	overrides := d.Get("override_project_labels").(*schema.Set)
 	for k := range project.Labels {
-		if v, ok := clusterLabels[k]; ok {
-			return errors.Errorf("cannot change labels inherited from project: %v=%v", k, v)
+		if v, ok := clusterLabels[k]; ok && !overrides.Contains(k) {
+			return errors.Errorf("cannot change labels inherited from project: %v=%v, add %v to override_project_labels to override it", k, v, k)
+		}
+	}
+	return nil
+}
+
+// checkClusterOverridesLabels fails plan when a key of override_project_labels is not set in labels,
+// the inherited label would be read into labels and the plan would never converge.
+func checkClusterOverridesLabels(d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("labels") || !d.NewValueKnown("override_project_labels") {
+		return nil
+	}
+	labels := d.Get("labels").(map[string]interface{})
+	for _, k := range d.Get("override_project_labels").(*schema.Set).List() {
+		if _, ok := labels[k.(string)]; !ok {
+			return errors.Errorf("override_project_labels: label %v is not set in labels, set it or remove it from override_project_labels", k)
 		}
 	}
 	return nil
 }
 
-func getClusterVersionToUse(c *gometakube.Client, prefix string) (string, error) {
-	versions, _, err := c.Clusters.Upgrades(context.Background())
+// clusterLabels splits labels of a cluster into ones set in configuration and ones inherited from project,
+// effective are all of them except internal ones.
+func clusterLabels(labels, projectLabels map[string]string, overrides *schema.Set) (own, inherited, effective map[string]string) {
+	own = make(map[string]string)
+	inherited = make(map[string]string)
+	effective = make(map[string]string)
+	for k, v := range labels {
+		if k == clusterIdempotencyLabel {
+			continue
+		}
+		effective[k] = v
+		if _, ok := projectLabels[k]; ok && !overrides.Contains(k) {
+			inherited[k] = v
+		} else {
+			own[k] = v
+		}
+	}
+	return own, inherited, effective
+}
+
+func getClusterVersionToUse(ctx context.Context, c *gometakube.Client, prefix string) (string, error) {
+	versions, _, err := c.Clusters.Upgrades(ctx)
 	if err != nil {
 		return "", errors.Wrap(err, "list cluster upgrades")
 	}
 	return maxVersionWithPrefix(versions, prefix)
 }
 
-func getClusterVersionToUpgradeInto(c *gometakube.Client, prj, dc, id string) (string, error) {
-	versions, _, err := c.Clusters.ClusterUpgrades(context.Background(), prj, dc, id)
+func getClusterVersionToUpgradeInto(ctx context.Context, c *gometakube.Client, prj, dc, id string) (string, error) {
+	versions, _, err := c.Clusters.ClusterUpgrades(ctx, prj, dc, id)
 	if err != nil {
 		return "", err
 	}
@@ -611,28 +1344,235 @@ func checkClusterAutoscaleValid(d *schema.ResourceData) (int, int, error) {
 	return minReplicas, maxReplicas, nil
 }
 
-func getClusterDatacenter(c *gometakube.Client, n string) (*gometakube.Datacenter, error) {
-	dc, _, err := c.Datacenters.Get(context.Background(), n)
+func checkClusterNodedeplDisk(d *schema.ResourceData) error {
+	if d.Get("nodedepl.0.root_volume_type").(string) != "" && d.Get("nodedepl.0.disk_size").(int) == 0 {
+		return errors.New("root_volume_type requires disk_size, nodes boot from flavor's local disk otherwise")
+	}
+	return nil
+}
+
+func getClusterDatacenter(ctx context.Context, c *gometakube.Client, n string) (*gometakube.Datacenter, error) {
+	dc, _, err := c.Datacenters.Get(ctx, n)
 	if err != nil {
 		return nil, errors.Wrap(err, "get datacenter")
 	}
 	return dc, nil
 }
 
-func getCluster(c *gometakube.Client, prj, dc, id string) (*gometakube.Cluster, error) {
-	obj, _, err := c.Clusters.Get(context.Background(), prj, dc, id)
+func getCluster(ctx context.Context, c *gometakube.Client, prj, dc, id string) (*gometakube.Cluster, error) {
+	obj, _, err := c.Clusters.Get(ctx, prj, dc, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "get cluster")
 	}
 	return obj, nil
 }
 
+// patchClusterIfUnchanged runs patch with etag read into state as If-Match precondition, see updateIfUnchanged,
+// read are the patchable fields cluster is expected to have. Etag of the patched cluster is recorded in state.
+func patchClusterIfUnchanged(ctx context.Context, d *schema.ResourceData, client *gometakube.Client, prj, dc string, read *gometakube.PatchClusterRequest, patch func(ctx context.Context) (*gometakube.Cluster, *http.Response, error)) (*gometakube.Cluster, error) {
+	var patched *gometakube.Cluster
+	var resp *http.Response
+	err := updateIfUnchanged(ctx, "cluster", d.Get("resource_version").(string), read, func() (interface{}, string, error) {
+		cluster, resp, err := client.Clusters.Get(ctx, prj, dc, d.Id())
+		if err != nil {
+			return nil, "", err
+		}
+		return clusterCurrentPatchRequest(cluster), gometakube.ETag(resp), nil
+	}, func(ctx context.Context) (err error) {
+		patched, resp, err = patch(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.Set("resource_version", gometakube.ETag(resp))
+	d.SetPartial("resource_version")
+	return patched, nil
+}
+
+// clusterOpenstackCredentialsPatch returns patch setting openstack credentials of a cluster.
+func clusterOpenstackCredentialsPatch(creds *gometakube.OpenstackCredentials) *gometakube.PatchClusterRequest {
+	ret := &gometakube.PatchClusterRequest{
+		Spec: &gometakube.PatchClusterRequestSpec{
+			Cloud: &gometakube.PatchClusterRequestSpecCloud{
+				OpenStack: &gometakube.PatchClusterRequestSpecCloudOpenstack{},
+			},
+		},
+	}
+	if creds != nil {
+		ret.Spec.Cloud.OpenStack.Username = creds.Username
+		ret.Spec.Cloud.OpenStack.Password = creds.Password
+		ret.Spec.Cloud.OpenStack.ApplicationCredentialID = creds.ApplicationCredentialID
+		ret.Spec.Cloud.OpenStack.ApplicationCredentialSecret = creds.ApplicationCredentialSecret
+	}
+	return ret
+}
+
+// clusterPreviousOpenstackCredentials returns credentials cluster was using before the change to creds.
+// Provider level ones are not kept in state, if cluster did not set its own, every field creds do not use
+// is returned set, so that merge patch clears it.
+func clusterPreviousOpenstackCredentials(d *schema.ResourceData, creds *gometakube.OpenstackCredentials) *gometakube.OpenstackCredentials {
+	old := func(k string) string {
+		v, _ := d.GetChange(k)
+		return v.(string)
+	}
+	ret := &gometakube.OpenstackCredentials{
+		Username:                    old("provider_username"),
+		Password:                    old("provider_password"),
+		ApplicationCredentialID:     old("application_credential_id"),
+		ApplicationCredentialSecret: old("application_credential_secret"),
+	}
+	if openstackCredentialsSet(ret) {
+		return ret
+	}
+	unused := func(v string) string {
+		if v == "" {
+			return "unknown"
+		}
+		return ""
+	}
+	return &gometakube.OpenstackCredentials{
+		Username:                    unused(creds.Username),
+		Password:                    unused(creds.Password),
+		ApplicationCredentialID:     unused(creds.ApplicationCredentialID),
+		ApplicationCredentialSecret: unused(creds.ApplicationCredentialSecret),
+	}
+}
+
+// clusterPatchRequest returns fields of cluster updated with merge patch on name, labels or audit_logging change.
+func clusterPatchRequest(cluster *gometakube.Cluster) *gometakube.PatchClusterRequest {
+	ret := &gometakube.PatchClusterRequest{
+		Name:   cluster.Name,
+		Labels: cluster.Labels,
+	}
+	if cluster.Spec != nil {
+		ret.Spec = &gometakube.PatchClusterRequestSpec{
+			AuditLogging: &cluster.Spec.AuditLogging,
+		}
+	}
+	return ret
+}
+
+// clusterStatePatchRequest returns patchable cluster fields as they were read into state.
+func clusterStatePatchRequest(d *schema.ResourceData) *gometakube.PatchClusterRequest {
+	name, _ := d.GetChange("name")
+	labels, _ := d.GetChange("effective_labels")
+	auditLogging, _ := d.GetChange("audit_logging")
+	ret := &gometakube.PatchClusterRequest{
+		Name: name.(string),
+		Spec: &gometakube.PatchClusterRequestSpec{
+			AuditLogging: &gometakube.ClusterSpecAuditLogging{
+				Enabled: auditLogging.(bool),
+			},
+		},
+	}
+	for k, v := range labels.(map[string]interface{}) {
+		if ret.Labels == nil {
+			ret.Labels = make(map[string]string)
+		}
+		ret.Labels[k] = v.(string)
+	}
+	return ret
+}
+
+// clusterCurrentPatchRequest returns patchable cluster fields the way they are read into state.
+func clusterCurrentPatchRequest(cluster *gometakube.Cluster) *gometakube.PatchClusterRequest {
+	ret := clusterPatchRequest(cluster)
+	_, _, ret.Labels = clusterLabels(cluster.Labels, nil, nil)
+	return ret
+}
+
 func clusterLabelsMap(d *schema.ResourceData) (ret map[string]string) {
 	return labelsMap(d)
 }
 
-func getClusterNodeDeployment(c *gometakube.Client, prj, dc, cls, name string) (*gometakube.NodeDeployment, error) {
-	items, _, err := c.NodeDeployments.List(context.Background(), prj, dc, cls)
+// clusterLabelsToPatch returns labels cluster should have: labels removed from configuration are dropped,
+// labels not managed by configuration, like ones inherited from project, are kept.
+func clusterLabelsToPatch(current map[string]string, old map[string]interface{}, labels map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range current {
+		if _, ok := old[k]; !ok {
+			ret[k] = v
+		}
+	}
+	for k, v := range labels {
+		ret[k] = v
+	}
+	return ret
+}
+
+// clusterIdempotencyLabel marks clusters created by the provider, so that
+// a cluster created by an apply interrupted before saving its ID is adopted instead of duplicated.
+const clusterIdempotencyLabel = "terraform-idempotency-key"
+
+// clusterIdempotencyKey is derived from attributes identifying cluster on create, so it is the same on retry.
+func clusterIdempotencyKey(d *schema.ResourceData) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		d.Get("project_id").(string),
+		d.Get("dc").(string),
+		d.Get("name").(string),
+	}, "/")))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+func clusterLabelsWithIdempotencyKey(labels map[string]string, key string) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range labels {
+		ret[k] = v
+	}
+	ret[clusterIdempotencyLabel] = key
+	return ret
+}
+
+// findClusterByIdempotencyKey returns cluster created with key, nil if there is none.
+func findClusterByIdempotencyKey(ctx context.Context, c *gometakube.Client, prj, dc, name, key string) (*gometakube.Cluster, error) {
+	items, _, err := c.Clusters.List(ctx, prj)
+	if err != nil {
+		return nil, errors.Wrap(err, "list clusters")
+	}
+	for _, item := range items {
+		if item.DeletionTimestamp != nil || item.Name != name || item.Labels[clusterIdempotencyLabel] != key {
+			continue
+		}
+		if item.Spec != nil && item.Spec.Cloud != nil && item.Spec.Cloud.DataCenter != dc {
+			continue
+		}
+		return &item, nil
+	}
+	return nil, nil
+}
+
+// clusterAdoptionConflict returns why cluster found by idempotency key is not the one
+// an interrupted create of this resource made, empty string if it is.
+func clusterAdoptionConflict(obj *gometakube.Cluster, nodedepls []gometakube.NodeDeployment, tenant, version, nodedepl string, createdAfter time.Time) string {
+	if obj.Spec == nil || obj.Spec.Cloud == nil || obj.Spec.Cloud.OpenStack == nil || obj.Spec.Cloud.OpenStack.Tenant != tenant {
+		return "tenant differs"
+	}
+	if obj.Spec.Version != version {
+		return fmt.Sprintf("version `%s` differs", obj.Spec.Version)
+	}
+	for _, item := range nodedepls {
+		if item.Name != nodedepl {
+			return fmt.Sprintf("node deployment `%s` differs", item.Name)
+		}
+	}
+	if obj.CreationTimestamp == nil || obj.CreationTimestamp.Before(createdAfter) {
+		return "it is older than create timeout"
+	}
+	return ""
+}
+
+func getClusterNodeDeployment(ctx context.Context, c *gometakube.Client, prj, dc, cls, name string) (*gometakube.NodeDeployment, error) {
+	ret, err := findClusterNodeDeployment(ctx, c, prj, dc, cls, name)
+	if err == nil && ret == nil {
+		return nil, errors.Errorf("find node deployment by name `%s`", name)
+	}
+	return ret, err
+}
+
+// findClusterNodeDeployment returns node deployment by name, nil if there is none.
+func findClusterNodeDeployment(ctx context.Context, c *gometakube.Client, prj, dc, cls, name string) (*gometakube.NodeDeployment, error) {
+	items, _, err := c.NodeDeployments.List(ctx, prj, dc, cls)
 	if err != nil {
 		return nil, errors.Wrap(err, "list node deployments")
 	}
@@ -641,10 +1581,21 @@ func getClusterNodeDeployment(c *gometakube.Client, prj, dc, cls, name string) (
 			return &item, nil
 		}
 	}
-	return nil, errors.Errorf("find node deployment by name `%s`", name)
+	return nil, nil
+}
+
+// nodeDeploymentStateList returns node deployment the way it is read into state.
+func nodeDeploymentStateList(nodedepl *gometakube.NodeDeployment) interface{} {
+	d := resourceCluster().Data(nil)
+	d.Set("nodedepl", nodeDeploymentUpdatesMap(nodedepl))
+	return d.Get("nodedepl")
 }
 
 func nodeDeploymentUpdatesMap(nodedepl *gometakube.NodeDeployment) []interface{} {
+	diskSize := 0
+	if nodedepl.Spec.Template.Cloud.Openstack.DiskSize != nil {
+		diskSize = *nodedepl.Spec.Template.Cloud.Openstack.DiskSize
+	}
 	return []interface{}{map[string]interface{}{
 		"name":     nodedepl.Name,
 		"replicas": nodedepl.Spec.Replicas,
@@ -652,8 +1603,26 @@ func nodeDeploymentUpdatesMap(nodedepl *gometakube.NodeDeployment) []interface{}
 			"min_replicas": nodedepl.Spec.MinReplicas,
 			"max_replicas": nodedepl.Spec.MaxReplicas,
 		}},
-		"flavor":          nodedepl.Spec.Template.Cloud.Openstack.Flavor,
-		"image":           nodedepl.Spec.Template.Cloud.Openstack.Image,
-		"use_floating_ip": nodedepl.Spec.Template.Cloud.Openstack.UseFloatingIP,
+		"paused":            nodedepl.Spec.Paused,
+		"rollout_strategy":  nodeDeploymentStrategyMap(nodedepl.Spec.Strategy),
+		"flavor":            nodedepl.Spec.Template.Cloud.Openstack.Flavor,
+		"image":             nodedepl.Spec.Template.Cloud.Openstack.Image,
+		"use_floating_ip":   nodedepl.Spec.Template.Cloud.Openstack.UseFloatingIP,
+		"disk_size":         diskSize,
+		"root_volume_type":  nodedepl.Spec.Template.Cloud.Openstack.RootDiskVolumeType,
+		"availability_zone": nodedepl.Spec.Template.Cloud.Openstack.AvailabilityZone,
+		"tags":              nodeDeploymentUserTags(nodedepl.Spec.Template.Cloud.Openstack.Tags),
+		"node_labels":       nodeDeploymentUserLabels(nodedepl.Spec.Template.Labels),
+		"taints":            nodeDeploymentTaintsList(nodedepl.Spec.Template.Taints),
+		"kubelet":           nodeDeploymentKubeletMap(nodedepl.Spec.Template.Kubelet),
+		"operating_system":  nodeDeploymentOSMap(nodedepl.Spec.Template.OperatingSystem),
 	}}
 }
+
+func clusterHealthMap(h *gometakube.ClusterHealth) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range h.Components() {
+		ret[k] = v.String()
+	}
+	return ret
+}
@@ -0,0 +1,263 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func resourceClusterAddon() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceClusterAddonCreate,
+		Read:   resourceClusterAddonRead,
+		Update: resourceClusterAddonUpdate,
+		Delete: resourceClusterAddonDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"dc": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"variables": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceClusterAddonCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutCreate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	name := d.Get("name").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	if err := checkClusterAddonInstallable(ctx, client, prj, dc.Spec.Seed, cls, name); err != nil {
+		return err
+	}
+	obj, _, err := client.Addons.Create(ctx, prj, dc.Spec.Seed, cls, &gometakube.Addon{
+		Name: name,
+		Spec: gometakube.AddonSpec{
+			Variables: addonVariables(d),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "create cluster addon")
+	}
+	d.SetId(obj.ID)
+	if err := waitForClusterAddonReconciled(ctx, client, prj, dc.Spec.Seed, cls, obj.ID, obj.Generation); err != nil {
+		return err
+	}
+	return resourceClusterAddonRead(d, m)
+}
+
+func resourceClusterAddonRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	obj, resp, err := client.Addons.Get(ctx, prj, dc.Spec.Seed, cls, d.Id())
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			// Addon or its cluster was deleted.
+			d.SetId("")
+			return nil
+		}
+		return errors.Wrap(err, "get cluster addon")
+	}
+	if obj.DeletionTimestamp != nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("name", obj.Name)
+	variables := make(map[string]string)
+	for k, v := range obj.Spec.Variables {
+		variables[k] = fmt.Sprint(v)
+	}
+	d.Set("variables", variables)
+	return nil
+}
+
+func resourceClusterAddonUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutUpdate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	from, to := clusterAddonVariablesChange(d)
+	obj, _, err := client.Addons.MergePatch(ctx, prj, dc.Spec.Seed, cls, d.Id(), from, to)
+	if err != nil {
+		return errors.Wrap(err, "patch cluster addon")
+	}
+	if err := waitForClusterAddonReconciled(ctx, client, prj, dc.Spec.Seed, cls, d.Id(), obj.Generation); err != nil {
+		return err
+	}
+	return resourceClusterAddonRead(d, m)
+}
+
+func resourceClusterAddonDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutDelete)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	if _, err := client.Addons.Delete(ctx, prj, dc.Spec.Seed, cls, d.Id()); err != nil {
+		return errors.Wrap(err, "delete cluster addon")
+	}
+	return waitForClusterAddonDelete(ctx, client, prj, dc.Spec.Seed, cls, d.Id())
+}
+
+func checkClusterAddonInstallable(ctx context.Context, client *gometakube.Client, prj, dc, cls, name string) error {
+	installable, _, err := client.Addons.Installable(ctx, prj, dc, cls)
+	if err != nil {
+		return errors.Wrap(err, "list installable addons")
+	}
+	for _, v := range installable {
+		if v == name {
+			return nil
+		}
+	}
+	available := make([]string, 0)
+	for _, v := range installable {
+		available = append(available, "* "+v)
+	}
+	return errors.Errorf("addon `%s` can't be installed into cluster `%s`. Consider changing to one of:\n%s",
+		name,
+		cls,
+		strings.Join(available, "\n"))
+}
+
+// waitForClusterAddonReconciled waits until controller reconciled addon generation returned by create or patch.
+func waitForClusterAddonReconciled(ctx context.Context, client *gometakube.Client, prj, dc, cls, id string, generation uint) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "wait cluster addon reconciled")
+		case <-ticker.C:
+		}
+		obj, _, err := client.Addons.Get(ctx, prj, dc, cls, id)
+		if err != nil {
+			continue
+		}
+		if done, err := clusterAddonReconciled(obj, generation); err != nil || done {
+			return err
+		}
+	}
+}
+
+// clusterAddonReconciled tells whether controller observed generation and applied the addon,
+// error is returned when reconciliation of that generation failed.
+func clusterAddonReconciled(obj *gometakube.Addon, generation uint) (bool, error) {
+	if obj.DeletionTimestamp != nil {
+		return false, errors.New("cluster addon is being deleted")
+	}
+	if obj.Status == nil || obj.Status.ObservedGeneration < generation {
+		return false, nil
+	}
+	c := obj.Status.Condition(gometakube.AddonConditionReconciled)
+	switch {
+	case c == nil:
+		return false, nil
+	case c.Status == "True":
+		return true, nil
+	case c.Status == "False":
+		return false, errors.Errorf("cluster addon reconciliation failed: %s %s", c.Reason, c.Message)
+	}
+	return false, nil
+}
+
+func waitForClusterAddonDelete(ctx context.Context, client *gometakube.Client, prj, dc, cls, id string) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "wait cluster addon delete")
+		case <-ticker.C:
+		}
+		_, resp, err := client.Addons.Get(ctx, prj, dc, cls, id)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return nil
+			}
+			return errors.Wrap(err, "get cluster addon")
+		}
+	}
+}
+
+// clusterAddonVariablesChange returns addon with old and new variables,
+// merge patch between them removes variables dropped from configuration.
+func clusterAddonVariablesChange(d *schema.ResourceData) (from, to *gometakube.Addon) {
+	old, _ := d.GetChange("variables")
+	from = &gometakube.Addon{
+		Name: d.Get("name").(string),
+		Spec: gometakube.AddonSpec{Variables: old.(map[string]interface{})},
+	}
+	to = &gometakube.Addon{
+		Name: d.Get("name").(string),
+		Spec: gometakube.AddonSpec{Variables: addonVariables(d)},
+	}
+	return from, to
+}
+
+func addonVariables(d *schema.ResourceData) map[string]interface{} {
+	ret := make(map[string]interface{})
+	for k, v := range d.Get("variables").(map[string]interface{}) {
+		ret[k] = v
+	}
+	return ret
+}
@@ -0,0 +1,110 @@
+package metakube
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func testAccMetakubeClusterAddonConfig(project, dc, tenant, username, password, replicas string) string {
+	return testAccMetakubeClusterConfig(project, dc, tenant, username, password) + `
+resource "metakube_cluster_addon" "dashboard" {
+	project_id = metakube_project.cluster-project.id
+	dc = metakube_cluster.bar.dc
+	cluster_id = metakube_cluster.bar.id
+
+	name = "dashboard"
+	variables = {
+		replicas = "` + replicas + `"
+	}
+}
+`
+}
+
+func TestAccMetakubeClusterAddon_Basic(t *testing.T) {
+	testDC := os.Getenv(accProviderDCEnvname)
+	testTenant := os.Getenv(accTenantEnvname)
+	testProviderUsername := os.Getenv(accProviderUsernameEnvname)
+	testProviderPassword := os.Getenv(accProviderPasswordEnvname)
+	projectName := acctest.RandString(8)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testEnvSet(t, APITokenEnvName)
+			testEnvSet(t, accProviderDCEnvname)
+			testEnvSet(t, accTenantEnvname)
+			testEnvSet(t, accProviderUsernameEnvname)
+			testEnvSet(t, accProviderPasswordEnvname)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetakubeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetakubeClusterAddonConfig(projectName, testDC, testTenant, testProviderUsername, testProviderPassword, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("metakube_cluster_addon.dashboard", "name", "dashboard"),
+					resource.TestCheckResourceAttr("metakube_cluster_addon.dashboard", "variables.replicas", "1"),
+				),
+			},
+			{
+				Config: testAccMetakubeClusterAddonConfig(projectName, testDC, testTenant, testProviderUsername, testProviderPassword, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("metakube_cluster_addon.dashboard", "variables.replicas", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestClusterAddonReconciled(t *testing.T) {
+	reconciled := func(status string) *gometakube.AddonStatus {
+		return &gometakube.AddonStatus{
+			ObservedGeneration: 2,
+			Conditions:         []gometakube.AddonCondition{{Type: gometakube.AddonConditionReconciled, Status: status, Reason: "ApplyFailed"}},
+		}
+	}
+	for _, tc := range []struct {
+		name    string
+		status  *gometakube.AddonStatus
+		done    bool
+		wantErr bool
+	}{
+		{name: "no status"},
+		{name: "old generation observed", status: &gometakube.AddonStatus{ObservedGeneration: 1, Conditions: reconciled("True").Conditions}},
+		{name: "no condition", status: &gometakube.AddonStatus{ObservedGeneration: 2}},
+		{name: "reconciled", status: reconciled("True"), done: true},
+		{name: "failed", status: reconciled("False"), wantErr: true},
+		{name: "unknown", status: reconciled("Unknown")},
+	} {
+		done, err := clusterAddonReconciled(&gometakube.Addon{Generation: 2, Status: tc.status}, 2)
+		if done != tc.done || (err != nil) != tc.wantErr {
+			t.Errorf("%s: want done %v and error %v, got %v and %v", tc.name, tc.done, tc.wantErr, done, err)
+		}
+	}
+}
+
+func TestClusterAddonVariablesChange(t *testing.T) {
+	d := resourceClusterAddon().Data(&terraform.InstanceState{
+		ID: "theaddon",
+		Attributes: map[string]string{
+			"name":              "dashboard",
+			"variables.%":       "2",
+			"variables.kept":    "1",
+			"variables.removed": "2",
+		},
+	})
+	d.Set("variables", map[string]interface{}{"kept": "1"})
+	from, to := clusterAddonVariablesChange(d)
+	patch, err := gometakube.NewMergePatch(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(patch)
+	if want, got := `{"spec":{"variables":{"removed":null}}}`, string(b); want != got {
+		t.Fatalf("want patch %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,131 @@
+package metakube
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceClusterV0 is the shape of cluster state written before schema versions were introduced.
+// Only attribute types matter here, validation and defaults are left out.
+func resourceClusterV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"sshkeys": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"dc": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"tenant": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"provider_username": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"provider_password": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"audit_logging": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"nodedepl": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+							Optional: true,
+						},
+						"replicas": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"autoscale": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"min_replicas": {
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+									"max_replicas": {
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+								},
+							},
+						},
+						"flavor": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"image": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"use_floating_ip": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceClusterStateUpgradeV0 fills defaults of attributes added without a state migration, so they don't show up in plan.
+// sshkeys are left as they are, in the form the configuration uses, ID or name.
+func resourceClusterStateUpgradeV0(rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	stateSetDefaults(rawState, map[string]interface{}{
+		"audit_logging":                    false,
+		"deletion_protection":              false,
+		"delete_volumes_on_destroy":        false,
+		"delete_load_balancers_on_destroy": false,
+		"wait_for_rollout":                 true,
+	})
+	if nodedepls, ok := rawState["nodedepl"].([]interface{}); ok {
+		for _, v := range nodedepls {
+			if nodedepl, ok := v.(map[string]interface{}); ok {
+				stateSetDefaults(nodedepl, map[string]interface{}{
+					"paused":          false,
+					"use_floating_ip": true,
+				})
+			}
+		}
+	}
+	return rawState, nil
+}
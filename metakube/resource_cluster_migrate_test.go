@@ -0,0 +1,68 @@
+package metakube
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func TestResourceClusterStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"project_id": "theproject",
+		"sshkeys":    []interface{}{"my-key", "key-2"},
+		"nodedepl": []interface{}{map[string]interface{}{
+			"name": "my-nodedepl",
+		}},
+		"deletion_protection": true,
+	}
+	got, err := resourceClusterStateUpgradeV0(rawState, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"project_id": "theproject",
+		"sshkeys":    []interface{}{"my-key", "key-2"},
+		"nodedepl": []interface{}{map[string]interface{}{
+			"name":            "my-nodedepl",
+			"paused":          false,
+			"use_floating_ip": true,
+		}},
+		"audit_logging":                    false,
+		"deletion_protection":              true,
+		"delete_volumes_on_destroy":        false,
+		"delete_load_balancers_on_destroy": false,
+		"wait_for_rollout":                 true,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want state %v, got %v", want, got)
+	}
+}
+
+func TestResourceClusterStateUpgradeV0KeepsSSHKeysAssigned(t *testing.T) {
+	keys := []gometakube.SSHKey{
+		{ID: "key-1", Name: "my-key"},
+		{ID: "key-2", Name: "other-key"},
+	}
+	assigned := map[string]bool{"key-1": true, "key-2": true}
+	client, closeServer := testSSHKeysServer(t, keys, assigned)
+	defer closeServer()
+
+	got, err := resourceClusterStateUpgradeV0(map[string]interface{}{
+		"sshkeys": []interface{}{"my-key", "other-key"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Configuration still references keys by name.
+	old := schema.NewSet(schema.HashString, got["sshkeys"].([]interface{}))
+	new := schema.NewSet(schema.HashString, []interface{}{"my-key", "other-key"})
+	if err := manageSSHKeysInCluster(context.Background(), client, old, new, "prj", "dc", "cls"); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"key-1": true, "key-2": true}; !reflect.DeepEqual(want, assigned) {
+		t.Fatalf("want assigned %v, got %v", want, assigned)
+	}
+}
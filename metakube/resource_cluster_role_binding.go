@@ -0,0 +1,207 @@
+package metakube
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+// RBAC subject kinds as returned by api.
+const (
+	rbacSubjectUser  = "User"
+	rbacSubjectGroup = "Group"
+)
+
+func resourceClusterRoleBinding() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceClusterRoleBindingCreate,
+		Read:   resourceClusterRoleBindingRead,
+		Delete: resourceClusterRoleBindingDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceClusterRoleBindingImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"dc": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"cluster_role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"user": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+			"group": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+		},
+	}
+}
+
+func resourceClusterRoleBindingCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutCreate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	role := d.Get("cluster_role").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	if err := checkClusterRoleExists(ctx, client, prj, dc.Spec.Seed, cls, role); err != nil {
+		return err
+	}
+	subject := &gometakube.ClusterRoleUser{
+		UserEmail: d.Get("user").(string),
+		Group:     d.Get("group").(string),
+	}
+	if _, _, err := client.RBAC.BindClusterRole(ctx, prj, dc.Spec.Seed, cls, role, subject); err != nil {
+		return errors.Wrap(err, "bind cluster role")
+	}
+	kind, name := rbacSubject(d)
+	d.SetId(rbacBindingID(prj, d.Get("dc").(string), cls, role, kind, name))
+	return resourceClusterRoleBindingRead(d, m)
+}
+
+func resourceClusterRoleBindingRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	role := d.Get("cluster_role").(string)
+	kind, name := rbacSubject(d)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	bindings, _, err := client.RBAC.ClusterBindings(ctx, prj, dc.Spec.Seed, cls)
+	if err != nil {
+		return errors.Wrap(err, "list cluster role bindings")
+	}
+	for _, binding := range bindings {
+		if binding.RoleRefName == role && rbacSubjectsContain(binding.Subjects, kind, name) {
+			return nil
+		}
+	}
+	// Subject is not bound to the role anymore.
+	d.SetId("")
+	return nil
+}
+
+func resourceClusterRoleBindingDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutDelete)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	role := d.Get("cluster_role").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	subject := &gometakube.ClusterRoleUser{
+		UserEmail: d.Get("user").(string),
+		Group:     d.Get("group").(string),
+	}
+	if _, err := client.RBAC.UnbindClusterRole(ctx, prj, dc.Spec.Seed, cls, role, subject); err != nil {
+		return errors.Wrap(err, "unbind cluster role")
+	}
+	return nil
+}
+
+func resourceClusterRoleBindingImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// Subject name goes last, it may contain separator, e.g. oidc:group.
+	parts := strings.SplitN(d.Id(), ":", 6)
+	if len(parts) != 6 {
+		return nil, errors.Errorf("unexpected ID format `%s`, want project_id:dc:cluster_id:cluster_role:User|Group:name", d.Id())
+	}
+	d.Set("project_id", parts[0])
+	d.Set("dc", parts[1])
+	d.Set("cluster_id", parts[2])
+	d.Set("cluster_role", parts[3])
+	if err := setRBACSubject(d, parts[4], parts[5]); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func checkClusterRoleExists(ctx context.Context, client *gometakube.Client, prj, dc, cls, role string) error {
+	names, _, err := client.RBAC.ClusterRoleNames(ctx, prj, dc, cls)
+	if err != nil {
+		return errors.Wrap(err, "list cluster roles")
+	}
+	available := make([]string, 0)
+	for _, item := range names {
+		if item.Name == role {
+			return nil
+		}
+		available = append(available, "* "+item.Name)
+	}
+	return errors.Errorf("cluster role `%s` not found. Consider changing to one of:\n%s",
+		role,
+		strings.Join(available, "\n"))
+}
+
+func rbacBindingID(parts ...string) string {
+	return strings.Join(parts, ":")
+}
+
+func rbacSubject(d *schema.ResourceData) (kind, name string) {
+	if v := d.Get("user").(string); v != "" {
+		return rbacSubjectUser, v
+	}
+	return rbacSubjectGroup, d.Get("group").(string)
+}
+
+func setRBACSubject(d *schema.ResourceData, kind, name string) error {
+	switch kind {
+	case rbacSubjectUser:
+		d.Set("user", name)
+	case rbacSubjectGroup:
+		d.Set("group", name)
+	default:
+		return errors.Errorf("unknown subject kind `%s`, want %s or %s", kind, rbacSubjectUser, rbacSubjectGroup)
+	}
+	return nil
+}
+
+func rbacSubjectsContain(subjects []gometakube.RBACSubject, kind, name string) bool {
+	for _, s := range subjects {
+		if s.Kind == kind && s.Name == name {
+			return true
+		}
+	}
+	return false
+}
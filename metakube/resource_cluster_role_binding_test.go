@@ -0,0 +1,72 @@
+package metakube
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func testAccMetakubeRoleBindingsConfig(project, dc, tenant, username, password string) string {
+	return testAccMetakubeClusterConfig(project, dc, tenant, username, password) + `
+resource "metakube_cluster_role_binding" "admins" {
+	project_id = metakube_project.cluster-project.id
+	dc = metakube_cluster.bar.dc
+	cluster_id = metakube_cluster.bar.id
+
+	cluster_role = "admin"
+	group = "oidc:admins"
+}
+
+resource "metakube_role_binding" "dev" {
+	project_id = metakube_project.cluster-project.id
+	dc = metakube_cluster.bar.dc
+	cluster_id = metakube_cluster.bar.id
+
+	namespace = "default"
+	role = "namespace-editor"
+	user = "dev@example.com"
+}
+`
+}
+
+func TestAccMetakubeRoleBindings_Basic(t *testing.T) {
+	testDC := os.Getenv(accProviderDCEnvname)
+	testTenant := os.Getenv(accTenantEnvname)
+	testProviderUsername := os.Getenv(accProviderUsernameEnvname)
+	testProviderPassword := os.Getenv(accProviderPasswordEnvname)
+	projectName := acctest.RandString(8)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testEnvSet(t, APITokenEnvName)
+			testEnvSet(t, accProviderDCEnvname)
+			testEnvSet(t, accTenantEnvname)
+			testEnvSet(t, accProviderUsernameEnvname)
+			testEnvSet(t, accProviderPasswordEnvname)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetakubeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetakubeRoleBindingsConfig(projectName, testDC, testTenant, testProviderUsername, testProviderPassword),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("metakube_cluster_role_binding.admins", "cluster_role", "admin"),
+					resource.TestCheckResourceAttr("metakube_cluster_role_binding.admins", "group", "oidc:admins"),
+					resource.TestCheckResourceAttr("metakube_role_binding.dev", "namespace", "default"),
+					resource.TestCheckResourceAttr("metakube_role_binding.dev", "user", "dev@example.com"),
+				),
+			},
+			{
+				ResourceName:      "metakube_cluster_role_binding.admins",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "metakube_role_binding.dev",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
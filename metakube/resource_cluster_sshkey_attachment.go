@@ -0,0 +1,113 @@
+package metakube
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+)
+
+func resourceClusterSSHKeyAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceClusterSSHKeyAttachmentCreate,
+		Read:   resourceClusterSSHKeyAttachmentRead,
+		Delete: resourceClusterSSHKeyAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceClusterSSHKeyAttachmentImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"dc": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"sshkey_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+		},
+	}
+}
+
+func resourceClusterSSHKeyAttachmentCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutCreate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	id := d.Get("sshkey_id").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	if _, _, err := client.SSHKeys.AssignToCluster(ctx, prj, dc.Spec.Seed, cls, id); err != nil {
+		return errors.Wrap(err, "assign sshkey to cluster")
+	}
+	d.SetId(strings.Join([]string{prj, d.Get("dc").(string), cls, id}, ":"))
+	return resourceClusterSSHKeyAttachmentRead(d, m)
+}
+
+func resourceClusterSSHKeyAttachmentRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	sshkeys, _, err := client.SSHKeys.ListAssigned(ctx, d.Get("project_id").(string), dc.Spec.Seed, d.Get("cluster_id").(string))
+	if err != nil {
+		return errors.Wrap(err, "list cluster sshkeys")
+	}
+	for _, key := range sshkeys {
+		if key.ID == d.Get("sshkey_id").(string) {
+			return nil
+		}
+	}
+	// Key is not assigned to the cluster anymore.
+	d.SetId("")
+	return nil
+}
+
+func resourceClusterSSHKeyAttachmentDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutDelete)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	if _, err := client.SSHKeys.RemoveFromCluster(ctx, d.Get("project_id").(string), dc.Spec.Seed, d.Get("cluster_id").(string), d.Get("sshkey_id").(string)); err != nil {
+		return errors.Wrap(err, "evict sshkey from cluster")
+	}
+	return nil
+}
+
+func resourceClusterSSHKeyAttachmentImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 4 {
+		return nil, errors.Errorf("unexpected ID format `%s`, want project_id:dc:cluster_id:sshkey_id", d.Id())
+	}
+	d.Set("project_id", parts[0])
+	d.Set("dc", parts[1])
+	d.Set("cluster_id", parts[2])
+	d.Set("sshkey_id", parts[3])
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,181 @@
+package metakube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func testAccMetakubeClusterSSHKeyAttachmentConfig(project, dc, tenant, username, password string) string {
+	return testAccMetakubeClusterConfig(project, dc, tenant, username, password) + `
+resource "metakube_sshkey" "other-key" {
+	project_id = metakube_project.cluster-project.id
+	name = "other"
+	public_key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAILmQoVCn0J1zaclM3+jPB9lL7MOnA/SbLZvGuUXDrH+m user@machine"
+}
+
+resource "metakube_cluster_sshkey_attachment" "other" {
+	project_id = metakube_project.cluster-project.id
+	dc = metakube_cluster.bar.dc
+	cluster_id = metakube_cluster.bar.id
+	sshkey_id = metakube_sshkey.other-key.id
+}
+`
+}
+
+func TestAccMetakubeClusterSSHKeyAttachment_Basic(t *testing.T) {
+	testDC := os.Getenv(accProviderDCEnvname)
+	testTenant := os.Getenv(accTenantEnvname)
+	testProviderUsername := os.Getenv(accProviderUsernameEnvname)
+	testProviderPassword := os.Getenv(accProviderPasswordEnvname)
+	projectName := acctest.RandString(8)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testEnvSet(t, APITokenEnvName)
+			testEnvSet(t, accProviderDCEnvname)
+			testEnvSet(t, accTenantEnvname)
+			testEnvSet(t, accProviderUsernameEnvname)
+			testEnvSet(t, accProviderPasswordEnvname)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetakubeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetakubeClusterSSHKeyAttachmentConfig(projectName, testDC, testTenant, testProviderUsername, testProviderPassword),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("metakube_cluster_sshkey_attachment.other", "sshkey_id", "metakube_sshkey.other-key", "id"),
+					// Attached key does not show up in cluster's sshkeys.
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "sshkeys.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "metakube_cluster_sshkey_attachment.other",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestFindSSHKeyID(t *testing.T) {
+	keys := []gometakube.SSHKey{
+		{ID: "key-1", Name: "dev"},
+		{ID: "key-2", Name: "ops"},
+		{ID: "key-3", Name: "ops"},
+	}
+	for _, tc := range []struct {
+		v       string
+		want    string
+		wantErr bool
+	}{
+		{v: "key-2", want: "key-2"},
+		{v: "dev", want: "key-1"},
+		{v: "ops", wantErr: true},
+		{v: "unknown", wantErr: true},
+	} {
+		got, err := findSSHKeyID(keys, tc.v)
+		if tc.wantErr != (err != nil) {
+			t.Fatalf("%s: want error %v, got %v", tc.v, tc.wantErr, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: want %s, got %s", tc.v, tc.want, got)
+		}
+	}
+}
+
+func TestClusterSSHKeyChanges(t *testing.T) {
+	keys := []gometakube.SSHKey{
+		{ID: "key-1", Name: "dev"},
+		{ID: "key-2", Name: "ops"},
+		{ID: "key-3", Name: "ops"},
+	}
+	set := func(v ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, v)
+	}
+	for _, tc := range []struct {
+		name         string
+		old, new     interface{}
+		wantUnassign []string
+		wantAssign   []string
+		wantErr      bool
+	}{
+		{name: "create", new: set("dev", "key-2"), wantAssign: []string{"key-1", "key-2"}},
+		{name: "replace", old: set("key-1"), new: set("key-2"), wantUnassign: []string{"key-1"}, wantAssign: []string{"key-2"}},
+		{name: "deleted from project", old: set("key-1", "gone"), new: set("key-1")},
+		{name: "ambiguous unassign", old: set("ops"), new: set("key-1"), wantErr: true},
+		{name: "ambiguous assign", old: set("key-1"), new: set("ops"), wantErr: true},
+	} {
+		unassign, assign, err := clusterSSHKeyChanges(keys, tc.old, tc.new)
+		if tc.wantErr != (err != nil) {
+			t.Fatalf("%s: want error %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if !reflect.DeepEqual(tc.wantUnassign, unassign) || !reflect.DeepEqual(tc.wantAssign, assign) {
+			t.Fatalf("%s: want unassign %v assign %v, got unassign %v assign %v", tc.name, tc.wantUnassign, tc.wantAssign, unassign, assign)
+		}
+	}
+}
+
+// testSSHKeysServer serves project keys and keeps cluster's assigned keys up to date.
+func testSSHKeysServer(t *testing.T, keys []gometakube.SSHKey, assigned map[string]bool) (*gometakube.Client, func()) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/projects/prj/sshkeys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keys)
+	})
+	mux.HandleFunc("/api/v1/projects/prj/dc/dc/clusters/cls/sshkeys", func(w http.ResponseWriter, r *http.Request) {
+		ret := make([]gometakube.SSHKey, 0)
+		for _, key := range keys {
+			if assigned[key.ID] {
+				ret = append(ret, key)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(ret)
+	})
+	mux.HandleFunc("/api/v1/projects/prj/dc/dc/clusters/cls/sshkeys/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/prj/dc/dc/clusters/cls/sshkeys/")
+		switch r.Method {
+		case http.MethodPut:
+			assigned[id] = true
+			fmt.Fprint(w, `{}`)
+		case http.MethodDelete:
+			delete(assigned, id)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	server := httptest.NewServer(mux)
+	client := gometakube.New()
+	client.BaseURL, _ = url.Parse(server.URL)
+	return client, server.Close
+}
+
+func TestManageSSHKeysInClusterNameToID(t *testing.T) {
+	keys := []gometakube.SSHKey{
+		{ID: "key-1", Name: "dev"},
+		{ID: "key-2", Name: "ops"},
+	}
+	assigned := map[string]bool{"key-1": true}
+	client, closeServer := testSSHKeysServer(t, keys, assigned)
+	defer closeServer()
+
+	old := schema.NewSet(schema.HashString, []interface{}{"dev"})
+	new := schema.NewSet(schema.HashString, []interface{}{"key-1", "ops"})
+	if err := manageSSHKeysInCluster(context.Background(), client, old, new, "prj", "dc", "cls"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"key-1": true, "key-2": true}
+	if !reflect.DeepEqual(want, assigned) {
+		t.Fatalf("want assigned %v, got %v", want, assigned)
+	}
+}
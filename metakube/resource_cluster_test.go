@@ -2,13 +2,21 @@ package metakube
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/pkg/errors"
 	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
@@ -40,7 +48,7 @@ resource "metakube_cluster" "bar" {
 		"version" = "alpha"
 	}
 	sshkeys = [
-		"dev"
+		metakube_sshkey.my-key.id
 	]
 	version = "1.15"
 	dc = "%s"
@@ -110,6 +118,36 @@ resource "metakube_cluster" "bar" {
 		flavor = "m1c.medium"
 		image = "Rescue Ubuntu 18.04 sys11"
 		use_floating_ip = true
+		disk_size = 30
+		tags = {
+			"cost-center" = "acc-test"
+		}
+		node_labels = {
+			"role" = "ingress"
+		}
+
+		rollout_strategy {
+			max_surge = "1"
+			max_unavailable = "0"
+		}
+
+		taints {
+			key = "dedicated"
+			value = "ingress"
+			effect = "NoSchedule"
+		}
+
+		kubelet {
+			max_pods = 50
+			eviction_hard = {
+				"memory.available" = "200Mi"
+			}
+		}
+
+		operating_system {
+			name = "ubuntu"
+			dist_upgrade_on_boot = true
+		}
 	}
 }
 
@@ -161,8 +199,15 @@ func TestAccMetakubeCluster_CreateAndInPlaceUpdates(t *testing.T) {
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "version", "1.15"),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "dc", testDC),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "audit_logging", "true"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "health.apiserver", "up"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "health.etcd", "up"),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "provider_username", testProviderUsername),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "provider_password", testProviderPassword),
+					resource.TestCheckResourceAttrSet("metakube_cluster.bar", "api_url"),
+					resource.TestMatchResourceAttr("metakube_cluster.bar", "actual_version", regexp.MustCompile(`^1\.15\.`)),
+					resource.TestCheckResourceAttrSet("metakube_cluster.bar", "created_at"),
+					resource.TestCheckResourceAttrSet("metakube_cluster.bar", "seed"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "type", "kubernetes"),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.#", "1"),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.name", "my-nodedepl"),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.replicas", "2"),
@@ -187,6 +232,16 @@ func TestAccMetakubeCluster_CreateAndInPlaceUpdates(t *testing.T) {
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.flavor", "m1c.medium"),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.image", "Rescue Ubuntu 18.04 sys11"),
 					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.use_floating_ip", "true"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.disk_size", "30"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.tags.cost-center", "acc-test"),
+					resource.TestCheckResourceAttrSet("metakube_cluster.bar", "nodedepl.0.availability_zone"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.node_labels.role", "ingress"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.paused", "false"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.rollout_strategy.0.max_surge", "1"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.taints.0.effect", "NoSchedule"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.kubelet.0.max_pods", "50"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.operating_system.0.name", "ubuntu"),
+					resource.TestCheckResourceAttr("metakube_cluster.bar", "nodedepl.0.operating_system.0.dist_upgrade_on_boot", "true"),
 				),
 			},
 		},
@@ -194,7 +249,7 @@ func TestAccMetakubeCluster_CreateAndInPlaceUpdates(t *testing.T) {
 }
 
 func testAccCheckMetakubeClusterDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*gometakube.Client)
+	client := testAccProvider.Meta().(*metakubeProviderMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "metakube_cluster" {
@@ -225,7 +280,7 @@ func testAccCheckClusterResourceCreated(r string) resource.TestCheckFunc {
 			return errors.Errorf("not found: %s", r)
 		}
 
-		client := testAccProvider.Meta().(*gometakube.Client)
+		client := testAccProvider.Meta().(*metakubeProviderMeta).client
 		projectID := rs.Primary.Attributes["project_id"]
 		dcName := rs.Primary.Attributes["dc"]
 		dc, _, err := client.Datacenters.Get(context.Background(), dcName)
@@ -250,7 +305,7 @@ func testAccCheckClustersNodeDeployment(r, name, flavor, image string, floatingI
 		if !ok {
 			return errors.Errorf("not found: %s", r)
 		}
-		client := testAccProvider.Meta().(*gometakube.Client)
+		client := testAccProvider.Meta().(*metakubeProviderMeta).client
 		projectID := rs.Primary.Attributes["project_id"]
 		dcName := rs.Primary.Attributes["dc"]
 		dc, _, err := client.Datacenters.Get(context.Background(), dcName)
@@ -289,3 +344,448 @@ func testAccCheckClustersNodeDeployment(r, name, flavor, image string, floatingI
 		return nil
 	}
 }
+
+func TestNodeDeploymentRolledOut(t *testing.T) {
+	nodedepl := &gometakube.NodeDeployment{
+		Spec: gometakube.NodeDeploymentSpec{Replicas: 2},
+		Status: &gometakube.NodeDeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           3,
+			UpdatedReplicas:    1,
+			ReadyReplicas:      2,
+			AvailableReplicas:  2,
+		},
+	}
+	if nodeDeploymentRolledOut(nodedepl, 2) {
+		t.Fatal("want rollout in progress while old node is not removed")
+	}
+	if want, got := "observed generation 2 of 2, replicas 3, updated 1/2, ready 2/2, available 2/2", nodeDeploymentRolloutProgress(nodedepl, 2); want != got {
+		t.Fatalf("want progress: %s, got: %s", want, got)
+	}
+	nodedepl.Status.Replicas = 2
+	nodedepl.Status.UpdatedReplicas = 2
+	if !nodeDeploymentRolledOut(nodedepl, 2) {
+		t.Fatal("want rollout done")
+	}
+	if nodeDeploymentRolledOut(nodedepl, 3) {
+		t.Fatal("want rollout in progress until new generation is observed")
+	}
+	nodedepl.Generation = 3
+	if nodeDeploymentRolledOut(nodedepl, 2) {
+		t.Fatal("want rollout in progress until generation of the object is observed")
+	}
+}
+
+func TestClusterIdempotencyKey(t *testing.T) {
+	raw := map[string]interface{}{
+		"project_id": "theproject",
+		"dc":         "dbl1",
+		"name":       "my-cluster",
+	}
+	key := clusterIdempotencyKey(schema.TestResourceDataRaw(t, resourceCluster().Schema, raw))
+	if want, got := key, clusterIdempotencyKey(schema.TestResourceDataRaw(t, resourceCluster().Schema, raw)); want != got {
+		t.Fatalf("want same key on retry: %s, got: %s", want, got)
+	}
+	raw["name"] = "other-cluster"
+	if clusterIdempotencyKey(schema.TestResourceDataRaw(t, resourceCluster().Schema, raw)) == key {
+		t.Fatal("want different key for other cluster")
+	}
+	labels := clusterLabelsWithIdempotencyKey(map[string]string{"version": "alpha"}, key)
+	if want := map[string]string{"version": "alpha", clusterIdempotencyLabel: key}; !reflect.DeepEqual(want, labels) {
+		t.Fatalf("want labels %v, got %v", want, labels)
+	}
+}
+
+func TestClusterAdoptionConflict(t *testing.T) {
+	created := time.Now()
+	obj := &gometakube.Cluster{
+		CreationTimestamp: &created,
+		Spec: &gometakube.ClusterSpec{
+			Version: "1.18.3",
+			Cloud: &gometakube.ClusterSpecCloud{
+				OpenStack: &gometakube.ClusterSpecCloudOpenstack{Tenant: "thetenant"},
+			},
+		},
+	}
+	nodedepls := []gometakube.NodeDeployment{{Name: "my-nodedepl"}}
+	recent := created.Add(-time.Minute)
+	if reason := clusterAdoptionConflict(obj, nodedepls, "thetenant", "1.18.3", "my-nodedepl", recent); reason != "" {
+		t.Fatalf("want cluster adopted, got %s", reason)
+	}
+	if reason := clusterAdoptionConflict(obj, nil, "thetenant", "1.18.3", "my-nodedepl", recent); reason != "" {
+		t.Fatalf("want cluster without node deployment yet adopted, got %s", reason)
+	}
+	for name, reason := range map[string]string{
+		"tenant":   clusterAdoptionConflict(obj, nodedepls, "othertenant", "1.18.3", "my-nodedepl", recent),
+		"version":  clusterAdoptionConflict(obj, nodedepls, "thetenant", "1.17.9", "my-nodedepl", recent),
+		"nodedepl": clusterAdoptionConflict(obj, nodedepls, "thetenant", "1.18.3", "other-nodedepl", recent),
+		"age":      clusterAdoptionConflict(obj, nodedepls, "thetenant", "1.18.3", "my-nodedepl", created.Add(time.Minute)),
+	} {
+		if reason == "" {
+			t.Fatalf("%s: want cluster not adopted", name)
+		}
+	}
+}
+
+func TestClusterCreateStageError(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]interface{}{})
+	d.SetId("thecluster")
+	err := clusterCreateStageError(d, "assign ssh keys", errors.New("no ssh key"))
+	if want, got := "cluster thecluster is created, but stage `assign ssh keys` failed, next apply resumes from it: no ssh key", err.Error(); want != got {
+		t.Fatalf("want error: %s, got: %s", want, got)
+	}
+}
+
+func TestRunClusterCreateStagesRecordsStage(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]interface{}{})
+	d.SetId("thecluster")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Stages before the resumed one are skipped, the resumed one fails as context is canceled.
+	if err := runClusterCreateStages(ctx, d, gometakube.New(), "theproject", "dbl1", "wait cluster is healthy"); err == nil {
+		t.Fatal("want update resuming create to fail")
+	}
+	if want, got := "wait cluster is healthy", d.Get("create_stage").(string); want != got {
+		t.Fatalf("want create stage %s, got %s", want, got)
+	}
+	// Failed create is not reported as an error, that would taint the cluster.
+	d.MarkNewResource()
+	if err := runClusterCreateStages(ctx, d, gometakube.New(), "theproject", "dbl1", "wait cluster is healthy"); err != nil {
+		t.Fatalf("want create not failing, got %v", err)
+	}
+	if d.Id() != "thecluster" {
+		t.Fatal("want ID kept, so that the cluster is not leaked")
+	}
+	if want, got := "cluster thecluster is created, but stage `wait cluster is healthy` failed", d.Get("create_error").(string); !strings.HasPrefix(got, want) {
+		t.Fatalf("want create error starting with %s, got %s", want, got)
+	}
+}
+
+func TestResumeClusterCreateDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "cls",
+		Attributes: map[string]string{
+			"name":         "my-cluster",
+			"create_stage": "wait cluster is healthy",
+			"create_error": "cluster cls is created, but stage `wait cluster is healthy` failed",
+		},
+	}
+	diff, err := resourceCluster().Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "my-cluster"}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr, ok := diff.Attributes["create_stage"]; !ok || attr.New != "" || diff.RequiresNew() {
+		t.Fatalf("want update clearing create stage, got %v", diff)
+	}
+}
+
+func TestResourceClusterReadWithoutNodeDeployment(t *testing.T) {
+	client, closeServer := testClusterReadServer(t, false, false)
+	defer closeServer()
+	meta := &metakubeProviderMeta{client: client}
+	if err := resourceClusterRead(testClusterReadData(""), meta); err == nil {
+		t.Fatal("want error for missing node deployment of created cluster")
+	}
+	d := testClusterReadData("wait node deployment is created")
+	if err := resourceClusterRead(d, meta); err != nil {
+		t.Fatalf("want cluster which create failed refreshed, got %v", err)
+	}
+	if want, got := "my-nodedepl", d.Get("nodedepl.0.name").(string); want != got {
+		t.Fatalf("want node deployment %s kept, got %s", want, got)
+	}
+	if want, got := "wait node deployment is created", d.Get("create_stage").(string); want != got {
+		t.Fatalf("want create stage %s kept, got %s", want, got)
+	}
+}
+
+func TestClusterLabelsToPatch(t *testing.T) {
+	current := map[string]string{
+		"project-label":         "inherited",
+		clusterIdempotencyLabel: "key",
+		"kept":                  "v",
+		"removed":               "v",
+		"changed":               "old",
+	}
+	old := map[string]interface{}{"kept": "v", "removed": "v", "changed": "old"}
+	got := clusterLabelsToPatch(current, old, map[string]string{"kept": "v", "changed": "new"})
+	want := map[string]string{
+		"project-label":         "inherited",
+		clusterIdempotencyLabel: "key",
+		"kept":                  "v",
+		"changed":               "new",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want labels %v, got %v", want, got)
+	}
+}
+
+func TestClusterLabels(t *testing.T) {
+	labels := map[string]string{
+		"team":                  "platform",
+		"env":                   "staging",
+		"cost-center":           "42",
+		clusterIdempotencyLabel: "key",
+	}
+	projectLabels := map[string]string{"env": "prod", "cost-center": "42"}
+	own, inherited, effective := clusterLabels(labels, projectLabels, schema.NewSet(schema.HashString, []interface{}{"env"}))
+	if want := map[string]string{"team": "platform", "env": "staging"}; !reflect.DeepEqual(want, own) {
+		t.Fatalf("want own labels %v, got %v", want, own)
+	}
+	if want := map[string]string{"cost-center": "42"}; !reflect.DeepEqual(want, inherited) {
+		t.Fatalf("want inherited labels %v, got %v", want, inherited)
+	}
+	if want := map[string]string{"team": "platform", "env": "staging", "cost-center": "42"}; !reflect.DeepEqual(want, effective) {
+		t.Fatalf("want effective labels %v, got %v", want, effective)
+	}
+}
+
+func TestWaitNodeDeploymentRolloutCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err := waitNodeDeploymentRollout(ctx, gometakube.New(), "theproject", "dbl1", "thecluster", "thenodedepl", 1)
+	if errors.Cause(err) != context.Canceled {
+		t.Fatalf("want context canceled, got: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("want wait to stop right away when interrupted")
+	}
+}
+
+func TestClusterOpenstackCredentialsPatch(t *testing.T) {
+	from := clusterOpenstackCredentialsPatch(&gometakube.OpenstackCredentials{Username: "user", Password: "pass"})
+	to := clusterOpenstackCredentialsPatch(&gometakube.OpenstackCredentials{ApplicationCredentialID: "id", ApplicationCredentialSecret: "secret"})
+	patch, err := gometakube.NewMergePatch(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"spec":{"cloud":{"openstack":{"applicationCredentialID":"id","applicationCredentialSecret":"secret","password":null,"username":null}}}}`
+	if string(b) != want {
+		t.Fatalf("want patch clearing username and password: %s, got: %s", want, b)
+	}
+}
+
+func TestClusterStatePatchRequest(t *testing.T) {
+	d := resourceCluster().Data(&terraform.InstanceState{
+		ID: "thecluster",
+		Attributes: map[string]string{
+			"name":                         "my-cluster",
+			"audit_logging":                "true",
+			"effective_labels.%":           "2",
+			"effective_labels.team":        "platform",
+			"effective_labels.cost-center": "42",
+		},
+	})
+	cluster := &gometakube.Cluster{
+		Name:   "my-cluster",
+		Labels: map[string]string{"team": "platform", "cost-center": "42", clusterIdempotencyLabel: "key"},
+		Spec:   &gometakube.ClusterSpec{AuditLogging: gometakube.ClusterSpecAuditLogging{Enabled: true}},
+	}
+	if diff, err := gometakube.NewMergePatch(clusterStatePatchRequest(d), clusterCurrentPatchRequest(cluster)); err != nil || !diff.Empty() {
+		t.Fatalf("want no changes, got %v, %v", diff, err)
+	}
+	cluster.Labels["team"] = "ops"
+	if diff, _ := gometakube.NewMergePatch(clusterStatePatchRequest(d), clusterCurrentPatchRequest(cluster)); diff.Empty() {
+		t.Fatal("want label change")
+	}
+}
+
+func TestCheckClusterOverridesLabels(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":                    "my-cluster",
+		"labels":                  map[string]interface{}{"team": "platform"},
+		"override_project_labels": []interface{}{"team"},
+	}
+	if _, err := resourceCluster().Diff(nil, terraform.NewResourceConfigRaw(raw), nil); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	raw["override_project_labels"] = []interface{}{"team", "cost-center"}
+	if _, err := resourceCluster().Diff(nil, terraform.NewResourceConfigRaw(raw), nil); err == nil || !strings.Contains(err.Error(), "cost-center") {
+		t.Fatalf("want error for override not set in labels, got %v", err)
+	}
+}
+
+// testClusterReadServer serves what cluster refresh reads: cluster cls in project prj and datacenter dbl1,
+// with node deployment nd-1 if withNodeDeployment is set, health fails unless healthOK is set.
+func testClusterReadServer(t *testing.T, withNodeDeployment, healthOK bool) (*gometakube.Client, func()) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/dc/dbl1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"metadata": {"name": "dbl1"}, "spec": {"seed": "seed1"}}`)
+	})
+	mux.HandleFunc("/api/v1/projects/prj", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "prj", "name": "my-project"}`)
+	})
+	mux.HandleFunc("/api/v1/projects/prj/dc/seed1/clusters/cls", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"c1"`)
+		fmt.Fprint(w, `{"id": "cls", "name": "my-cluster", "type": "kubernetes", "spec": {"version": "1.18.3", "cloud": {"dc": "dbl1"}}}`)
+	})
+	nodeDeploymentJSON := `{"id": "nd-1", "name": "my-nodedepl", "spec": {"replicas": 1, "template": {"cloud": {"openstack": {"flavor": "m1.small", "image": "Ubuntu"}}}}}`
+	mux.HandleFunc("/api/v1/projects/prj/dc/seed1/clusters/cls/nodedeployments", func(w http.ResponseWriter, r *http.Request) {
+		if withNodeDeployment {
+			fmt.Fprint(w, `[`+nodeDeploymentJSON+`]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/api/v1/projects/prj/dc/seed1/clusters/cls/nodedeployments/nd-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"n1"`)
+		fmt.Fprint(w, nodeDeploymentJSON)
+	})
+	mux.HandleFunc("/api/v1/projects/prj/dc/seed1/clusters/cls/sshkeys", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/api/v1/projects/prj/dc/seed1/clusters/cls/health", func(w http.ResponseWriter, r *http.Request) {
+		if !healthOK {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"apiserver": 1, "controller": 1, "etcd": 1, "machineController": 1, "scheduler": 1, "cloudProviderInfrastructure": 1, "userClusterControllerManager": 1}`)
+	})
+	server := httptest.NewServer(mux)
+	client := gometakube.New()
+	client.BaseURL, _ = url.Parse(server.URL)
+	return client, server.Close
+}
+
+func testClusterReadData(createStage string) *schema.ResourceData {
+	return resourceCluster().Data(&terraform.InstanceState{
+		ID: "cls",
+		Attributes: map[string]string{
+			"project_id":      "prj",
+			"dc":              "dbl1",
+			"version":         "1.18",
+			"create_stage":    createStage,
+			"nodedepl.#":      "1",
+			"nodedepl.0.name": "my-nodedepl",
+		},
+	})
+}
+
+func TestResourceClusterReadHealthFails(t *testing.T) {
+	client, closeServer := testClusterReadServer(t, true, false)
+	defer closeServer()
+	d := testClusterReadData("")
+	if err := resourceClusterRead(d, &metakubeProviderMeta{client: client}); err != nil {
+		t.Fatalf("want refresh not failing on health, got %v", err)
+	}
+	if health := d.Get("health").(map[string]interface{}); len(health) != 0 {
+		t.Fatalf("want health unset, got %v", health)
+	}
+	if want, got := `"c1"`, d.Get("resource_version").(string); want != got {
+		t.Fatalf("want resource version %s, got %s", want, got)
+	}
+}
+
+func TestNodeDeploymentRolloutGeneration(t *testing.T) {
+	spec := func(replicas uint) gometakube.NodeDeploymentSpec {
+		return gometakube.NodeDeploymentSpec{Replicas: replicas}
+	}
+	status := &gometakube.NodeDeploymentStatus{ObservedGeneration: 4}
+	for _, tc := range []struct {
+		name          string
+		before, after gometakube.NodeDeployment
+		want          uint
+	}{
+		{
+			name:   "generation increased",
+			before: gometakube.NodeDeployment{Generation: 4, Spec: spec(1), Status: status},
+			after:  gometakube.NodeDeployment{Generation: 5, Spec: spec(2)},
+			want:   5,
+		},
+		{
+			name:   "generation unchanged",
+			before: gometakube.NodeDeployment{Generation: 4, Spec: spec(1), Status: status},
+			after:  gometakube.NodeDeployment{Generation: 4, Spec: spec(1)},
+			want:   0,
+		},
+		{
+			name:   "generation not reported, spec changed",
+			before: gometakube.NodeDeployment{Spec: spec(1), Status: status},
+			after:  gometakube.NodeDeployment{Spec: spec(2)},
+			want:   5,
+		},
+		{
+			name:   "generation not reported, spec unchanged",
+			before: gometakube.NodeDeployment{Spec: spec(1), Status: status},
+			after:  gometakube.NodeDeployment{Spec: spec(1)},
+			want:   0,
+		},
+	} {
+		if got := nodeDeploymentRolloutGeneration(&tc.before, &tc.after); tc.want != got {
+			t.Fatalf("%s: want generation %d, got %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestPatchClusterIfUnchanged(t *testing.T) {
+	var ifMatch []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/projects/prj/dc/seed1/clusters/cls", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+			if r.Header.Get("If-Match") != `"c2"` {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			w.Header().Set("ETag", `"c3"`)
+		} else {
+			// Cluster status changed since it was read.
+			w.Header().Set("ETag", `"c2"`)
+		}
+		fmt.Fprint(w, `{"id": "cls", "name": "my-cluster", "spec": {}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := gometakube.New()
+	client.BaseURL, _ = url.Parse(server.URL)
+	patch := func(ctx context.Context) (*gometakube.Cluster, *http.Response, error) {
+		return client.Clusters.Patch(ctx, "prj", "seed1", "cls", &gometakube.PatchClusterRequest{Spec: &gometakube.PatchClusterRequestSpec{Version: "1.18.3"}})
+	}
+	newData := func() *schema.ResourceData {
+		return resourceCluster().Data(&terraform.InstanceState{ID: "cls", Attributes: map[string]string{"resource_version": `"c1"`}})
+	}
+
+	d := newData()
+	read := clusterCurrentPatchRequest(&gometakube.Cluster{Name: "my-cluster", Spec: &gometakube.ClusterSpec{}})
+	if _, err := patchClusterIfUnchanged(context.Background(), d, client, "prj", "seed1", read, patch); err != nil {
+		t.Fatalf("want patch retried with current etag, got %v", err)
+	}
+	if want, got := []string{`"c1"`, `"c2"`}, ifMatch; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want If-Match %v, got %v", want, got)
+	}
+	if want, got := `"c3"`, d.Get("resource_version").(string); want != got {
+		t.Fatalf("want resource version %s, got %s", want, got)
+	}
+
+	ifMatch = nil
+	d = newData()
+	read.Name = "renamed-in-ui"
+	if _, err := patchClusterIfUnchanged(context.Background(), d, client, "prj", "seed1", read, patch); err == nil || !strings.Contains(err.Error(), "changed concurrently") {
+		t.Fatalf("want conflict error, got %v", err)
+	}
+	if want, got := []string{`"c1"`}, ifMatch; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want If-Match %v, got %v", want, got)
+	}
+}
+
+func TestClusterPreviousProviderOpenstackCredentials(t *testing.T) {
+	// Cluster used provider level credentials, not kept in state.
+	d := resourceCluster().Data(&terraform.InstanceState{ID: "thecluster", Attributes: map[string]string{"name": "my-cluster"}})
+	creds := &gometakube.OpenstackCredentials{ApplicationCredentialID: "id", ApplicationCredentialSecret: "secret"}
+	patch, err := gometakube.NewMergePatch(clusterOpenstackCredentialsPatch(clusterPreviousOpenstackCredentials(d, creds)), clusterOpenstackCredentialsPatch(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"spec":{"cloud":{"openstack":{"applicationCredentialID":"id","applicationCredentialSecret":"secret","password":null,"username":null}}}}`
+	if string(b) != want {
+		t.Fatalf("want patch setting new credentials and clearing the rest: %s, got: %s", want, b)
+	}
+}
@@ -2,6 +2,7 @@ package metakube
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -16,37 +17,61 @@ func resourceProject() *schema.Resource {
 		Update: resourceProjectUpdate,
 		Delete: resourceProjectDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceProjectV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceProjectStateUpgradeV0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"labels": &schema.Schema{
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem:     schema.TypeString,
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         schema.TypeString,
+				ValidateFunc: validateLabels,
+			},
+			"deletion_protection": deletionProtectionSchema(),
+			"resource_version": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version (ETag) of the project read, updates fail if the project was changed since.",
 			},
 		},
 	}
 }
 
 func resourceProjectCreate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutCreate)
+	defer cancel()
 	create := &gometakube.ProjectCreateAndUpdateRequest{
 		Name:   d.Get("name").(string),
 		Labels: projectLabelsMap(d),
 	}
-	client := meta.(*gometakube.Client)
-	project, _, err := client.Projects.Create(context.Background(), create)
+	client := meta.(*metakubeProviderMeta).client
+	project, _, err := client.Projects.Create(ctx, create)
 	if err != nil {
 		return errors.Wrap(err, "create project: %v")
 	}
 	d.SetId(project.ID)
-	return waitProjectCreatedAndActive(client, project.ID)
+	return waitProjectCreatedAndActive(ctx, client, project.ID)
 }
 
 func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
-	c := meta.(*gometakube.Client)
-	obj, _, err := c.Projects.Get(context.Background(), d.Id())
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+	c := meta.(*metakubeProviderMeta).client
+	obj, resp, err := c.Projects.Get(ctx, d.Id())
 	if err != nil {
 		return err
 	}
@@ -57,10 +82,13 @@ func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	d.Set("name", obj.Name)
 	d.Set("labels", obj.Labels)
+	d.Set("resource_version", gometakube.ETag(resp))
 	return nil
 }
 
 func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutUpdate)
+	defer cancel()
 	d.Partial(true)
 	defer d.Partial(false)
 
@@ -68,8 +96,19 @@ func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
 		Name:   d.Get("name").(string),
 		Labels: projectLabelsMap(d),
 	}
-	client := meta.(*gometakube.Client)
-	updated, _, err := client.Projects.Update(context.Background(), d.Id(), update)
+	client := meta.(*metakubeProviderMeta).client
+	var updated *gometakube.Project
+	var resp *http.Response
+	err := updateIfUnchanged(ctx, "project", d.Get("resource_version").(string), projectStateUpdateRequest(d), func() (interface{}, string, error) {
+		current, resp, err := client.Projects.Get(ctx, d.Id())
+		if err != nil {
+			return nil, "", err
+		}
+		return projectUpdateRequest(current), gometakube.ETag(resp), nil
+	}, func(ctx context.Context) (err error) {
+		updated, resp, err = client.Projects.Update(ctx, d.Id(), update)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -78,15 +117,22 @@ func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
 		d.SetId("")
 		return nil
 	}
+	d.Set("resource_version", gometakube.ETag(resp))
 	d.SetPartial("name")
 	d.SetPartial("labels")
+	d.SetPartial("resource_version")
 
 	return nil
 }
 
 func resourceProjectDelete(d *schema.ResourceData, meta interface{}) error {
-	c := meta.(*gometakube.Client)
-	_, err := c.Projects.Delete(context.Background(), d.Id())
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+	if err := checkDeletionProtection(d, "project"); err != nil {
+		return err
+	}
+	c := meta.(*metakubeProviderMeta).client
+	_, err := c.Projects.Delete(ctx, d.Id())
 	if err != nil {
 		return err
 	}
@@ -94,22 +140,47 @@ func resourceProjectDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func waitProjectCreatedAndActive(client *gometakube.Client, id string) error {
+func waitProjectCreatedAndActive(ctx context.Context, client *gometakube.Client, id string) error {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
-	timeout := 120
-	n := 0
-	for range ticker.C {
-		project, _, err := client.Projects.Get(context.Background(), id)
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "wait project is active")
+		case <-ticker.C:
+		}
+		project, _, err := client.Projects.Get(ctx, id)
 		if err == nil && project.Status == "Active" {
 			return nil
 		}
-		if n > timeout {
-			return errors.New("project not active timeout")
+	}
+}
+
+// projectUpdateRequest returns fields of project overwritten by update.
+func projectUpdateRequest(project *gometakube.Project) *gometakube.ProjectCreateAndUpdateRequest {
+	ret := &gometakube.ProjectCreateAndUpdateRequest{
+		Name: project.Name,
+	}
+	if len(project.Labels) != 0 {
+		ret.Labels = project.Labels
+	}
+	return ret
+}
+
+// projectStateUpdateRequest returns project fields as they were read into state.
+func projectStateUpdateRequest(d *schema.ResourceData) *gometakube.ProjectCreateAndUpdateRequest {
+	name, _ := d.GetChange("name")
+	labels, _ := d.GetChange("labels")
+	ret := &gometakube.ProjectCreateAndUpdateRequest{
+		Name: name.(string),
+	}
+	for k, v := range labels.(map[string]interface{}) {
+		if ret.Labels == nil {
+			ret.Labels = make(map[string]string)
 		}
-		n++
+		ret.Labels[k] = v.(string)
 	}
-	return nil
+	return ret
 }
 
 func projectLabelsMap(d *schema.ResourceData) (ret map[string]string) {
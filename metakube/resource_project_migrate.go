@@ -0,0 +1,30 @@
+package metakube
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceProjectV0 is the shape of project state written before schema versions were introduced.
+func resourceProjectV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// resourceProjectStateUpgradeV0 fills defaults of attributes added without a state migration.
+func resourceProjectStateUpgradeV0(rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	stateSetDefaults(rawState, map[string]interface{}{
+		"deletion_protection": false,
+	})
+	return rawState, nil
+}
@@ -0,0 +1,16 @@
+package metakube
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourceProjectStateUpgradeV0(t *testing.T) {
+	got, err := resourceProjectStateUpgradeV0(map[string]interface{}{"name": "my-project"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"name": "my-project", "deletion_protection": false}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want state %v, got %v", want, got)
+	}
+}
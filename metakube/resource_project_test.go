@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/pkg/errors"
-	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
 )
 
 const (
@@ -52,7 +51,7 @@ func TestAccMetakubeProject_Basic(t *testing.T) {
 }
 
 func testAccCheckMetakubeProjectDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*gometakube.Client)
+	client := testAccProvider.Meta().(*metakubeProviderMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "metakube_project" {
@@ -81,7 +80,7 @@ func testAccCheckProjectResourceExist(r, name string, labels map[string]string)
 			return errors.Errorf("not found %s", r)
 		}
 
-		client := testAccProvider.Meta().(*gometakube.Client)
+		client := testAccProvider.Meta().(*metakubeProviderMeta).client
 
 		if obj, _, err := client.Projects.Get(context.Background(), rs.Primary.ID); err != nil {
 			return errors.Wrap(err, "get project")
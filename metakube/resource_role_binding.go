@@ -0,0 +1,180 @@
+package metakube
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func resourceRoleBinding() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRoleBindingCreate,
+		Read:   resourceRoleBindingRead,
+		Delete: resourceRoleBindingDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceRoleBindingImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"dc": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"user": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+			"group": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+		},
+	}
+}
+
+func resourceRoleBindingCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutCreate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	namespace := d.Get("namespace").(string)
+	role := d.Get("role").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	if err := checkRoleExists(ctx, client, prj, dc.Spec.Seed, cls, namespace, role); err != nil {
+		return err
+	}
+	subject := &gometakube.RoleUser{
+		UserEmail: d.Get("user").(string),
+		Group:     d.Get("group").(string),
+	}
+	if _, _, err := client.RBAC.BindRole(ctx, prj, dc.Spec.Seed, cls, namespace, role, subject); err != nil {
+		return errors.Wrap(err, "bind role")
+	}
+	kind, name := rbacSubject(d)
+	d.SetId(rbacBindingID(prj, d.Get("dc").(string), cls, namespace, role, kind, name))
+	return resourceRoleBindingRead(d, m)
+}
+
+func resourceRoleBindingRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	namespace := d.Get("namespace").(string)
+	role := d.Get("role").(string)
+	kind, name := rbacSubject(d)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	bindings, _, err := client.RBAC.RoleBindings(ctx, prj, dc.Spec.Seed, cls)
+	if err != nil {
+		return errors.Wrap(err, "list role bindings")
+	}
+	for _, binding := range bindings {
+		if binding.Namespace == namespace && binding.RoleRefName == role && rbacSubjectsContain(binding.Subjects, kind, name) {
+			return nil
+		}
+	}
+	// Subject is not bound to the role anymore.
+	d.SetId("")
+	return nil
+}
+
+func resourceRoleBindingDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutDelete)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	cls := d.Get("cluster_id").(string)
+	dc, err := getClusterDatacenter(ctx, client, d.Get("dc").(string))
+	if err != nil {
+		return err
+	}
+	subject := &gometakube.RoleUser{
+		UserEmail: d.Get("user").(string),
+		Group:     d.Get("group").(string),
+	}
+	if _, err := client.RBAC.UnbindRole(ctx, prj, dc.Spec.Seed, cls, d.Get("namespace").(string), d.Get("role").(string), subject); err != nil {
+		return errors.Wrap(err, "unbind role")
+	}
+	return nil
+}
+
+func resourceRoleBindingImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// Subject name goes last, it may contain separator, e.g. oidc:group.
+	parts := strings.SplitN(d.Id(), ":", 7)
+	if len(parts) != 7 {
+		return nil, errors.Errorf("unexpected ID format `%s`, want project_id:dc:cluster_id:namespace:role:User|Group:name", d.Id())
+	}
+	d.Set("project_id", parts[0])
+	d.Set("dc", parts[1])
+	d.Set("cluster_id", parts[2])
+	d.Set("namespace", parts[3])
+	d.Set("role", parts[4])
+	if err := setRBACSubject(d, parts[5], parts[6]); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func checkRoleExists(ctx context.Context, client *gometakube.Client, prj, dc, cls, namespace, role string) error {
+	names, _, err := client.RBAC.RoleNames(ctx, prj, dc, cls)
+	if err != nil {
+		return errors.Wrap(err, "list roles")
+	}
+	available := make([]string, 0)
+	for _, item := range names {
+		for _, ns := range item.Namespace {
+			if item.Name == role && ns == namespace {
+				return nil
+			}
+			available = append(available, "* "+ns+"/"+item.Name)
+		}
+	}
+	return errors.Errorf("role `%s` not found in namespace `%s`. Consider changing to one of:\n%s",
+		role,
+		namespace,
+		strings.Join(available, "\n"))
+}
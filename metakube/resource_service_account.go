@@ -0,0 +1,116 @@
+package metakube
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func resourceServiceAccount() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceServiceAccountCreate,
+		Read:   resourceServiceAccountRead,
+		Update: resourceServiceAccountUpdate,
+		Delete: resourceServiceAccountDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"group": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"editors", "viewers"}, false),
+			},
+		},
+	}
+}
+
+func resourceServiceAccountCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutCreate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	v, _, err := client.ServiceAccounts.Create(ctx, d.Get("project_id").(string), &gometakube.ServiceAccount{
+		Name:  d.Get("name").(string),
+		Group: d.Get("group").(string),
+	})
+	if err != nil {
+		return errors.Wrap(err, "create service account")
+	}
+	d.SetId(v.ID)
+	return nil
+}
+
+func resourceServiceAccountRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	v, err := getServiceAccount(ctx, client, d.Get("project_id").(string), d.Id())
+	if err != nil {
+		return err
+	}
+	if v == nil || v.DeletionTimestamp != nil {
+		// Service account not found in the project, it surely was deleted.
+		d.SetId("")
+		return nil
+	}
+	d.Set("name", v.Name)
+	d.Set("group", serviceAccountGroupPrefix(v.Group))
+	return nil
+}
+
+func resourceServiceAccountUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutUpdate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	_, _, err := client.ServiceAccounts.Update(ctx, d.Get("project_id").(string), d.Id(), &gometakube.ServiceAccount{
+		ID:    d.Id(),
+		Name:  d.Get("name").(string),
+		Group: d.Get("group").(string),
+	})
+	if err != nil {
+		return errors.Wrap(err, "update service account")
+	}
+	return resourceServiceAccountRead(d, m)
+}
+
+func resourceServiceAccountDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutDelete)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	_, err := client.ServiceAccounts.Delete(ctx, d.Get("project_id").(string), d.Id())
+	return err
+}
+
+func getServiceAccount(ctx context.Context, client *gometakube.Client, prj, id string) (*gometakube.ServiceAccount, error) {
+	items, _, err := client.ServiceAccounts.List(ctx, prj)
+	if err != nil {
+		return nil, errors.Wrap(err, "list service accounts")
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// serviceAccountGroupPrefix trims project id api appends to the group name, e.g. editors-<project id>.
+func serviceAccountGroupPrefix(group string) string {
+	if i := strings.Index(group, "-"); i != -1 {
+		return group[:i]
+	}
+	return group
+}
@@ -0,0 +1,90 @@
+package metakube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+)
+
+const (
+	testAccServiceAccountConfig = `
+provider "metakube" {
+}
+
+resource "metakube_project" "sa-project" {
+	name = "foo"
+	labels = {}
+}
+
+resource "metakube_service_account" "ci" {
+	project_id = metakube_project.sa-project.id
+
+	name = "ci"
+	group = "editors"
+}
+
+resource "metakube_service_account_token" "pipeline" {
+	project_id = metakube_project.sa-project.id
+	service_account_id = metakube_service_account.ci.id
+
+	name = "pipeline"
+	rotation_trigger = "%s"
+}
+`
+)
+
+func TestAccMetakubeServiceAccount_Basic(t *testing.T) {
+	var token string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testEnvSet(t, APITokenEnvName)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetakubeProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccServiceAccountConfig, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("metakube_service_account.ci", "name", "ci"),
+					resource.TestCheckResourceAttr("metakube_service_account.ci", "group", "editors"),
+					resource.TestCheckResourceAttr("metakube_service_account_token.pipeline", "name", "pipeline"),
+					resource.TestCheckResourceAttrSet("metakube_service_account_token.pipeline", "token"),
+					testAccStoreAttr("metakube_service_account_token.pipeline", "token", &token),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccServiceAccountConfig, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAttrChanged("metakube_service_account_token.pipeline", "token", &token),
+				),
+			},
+		},
+	})
+}
+
+func testAccStoreAttr(r, attr string, v *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[r]
+		if !ok {
+			return errors.Errorf("not found: %s", r)
+		}
+		*v = rs.Primary.Attributes[attr]
+		return nil
+	}
+}
+
+func testAccCheckAttrChanged(r, attr string, old *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[r]
+		if !ok {
+			return errors.Errorf("not found: %s", r)
+		}
+		if got := rs.Primary.Attributes[attr]; got == *old {
+			return errors.Errorf("want %s.%s changed, got the same value", r, attr)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,152 @@
+package metakube
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"gitlab.com/furkhat/terraform-provider-metakube/gometakube"
+)
+
+func resourceServiceAccountToken() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceServiceAccountTokenCreate,
+		Read:   resourceServiceAccountTokenRead,
+		Update: resourceServiceAccountTokenUpdate,
+		Delete: resourceServiceAccountTokenDelete,
+
+		CustomizeDiff: customdiff.All(
+			customdiff.ComputedIf("token", serviceAccountTokenRotated),
+			customdiff.ComputedIf("expiry", serviceAccountTokenRotated),
+		),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"service_account_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Any change of the value regenerates the token in place.",
+			},
+			"token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"expiry": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// serviceAccountTokenRotated tells token is regenerated on apply, so its value is not known at plan.
+func serviceAccountTokenRotated(d *schema.ResourceDiff, _ interface{}) bool {
+	return d.Id() != "" && d.HasChange("rotation_trigger")
+}
+
+func resourceServiceAccountTokenCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutCreate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	sa := d.Get("service_account_id").(string)
+	v, _, err := client.ServiceAccounts.CreateToken(ctx, prj, sa, &gometakube.ServiceAccountToken{
+		Name: d.Get("name").(string),
+	})
+	if err != nil {
+		return errors.Wrap(err, "create service account token")
+	}
+	d.SetId(v.ID)
+	// Token value is returned only once and can't be read back later.
+	d.Set("token", v.Token)
+	return resourceServiceAccountTokenRead(d, m)
+}
+
+func resourceServiceAccountTokenRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	sa := d.Get("service_account_id").(string)
+	tokens, resp, err := client.ServiceAccounts.ListTokens(ctx, prj, sa)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			// Service account was deleted together with its tokens.
+			d.SetId("")
+			return nil
+		}
+		return errors.Wrap(err, "list service account tokens")
+	}
+	var v *gometakube.ServiceAccountToken
+	for i := range tokens {
+		if tokens[i].ID == d.Id() {
+			v = &tokens[i]
+		}
+	}
+	if v == nil || v.DeletionTimestamp != nil {
+		// Token not found, it surely was deleted.
+		d.SetId("")
+		return nil
+	}
+	d.Set("name", v.Name)
+	if v.Expiry != nil {
+		d.Set("expiry", v.Expiry.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func resourceServiceAccountTokenUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutUpdate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	sa := d.Get("service_account_id").(string)
+	if d.HasChange("rotation_trigger") {
+		v, _, err := client.ServiceAccounts.RotateToken(ctx, prj, sa, &gometakube.ServiceAccountToken{
+			ID:   d.Id(),
+			Name: d.Get("name").(string),
+		})
+		if err != nil {
+			return errors.Wrap(err, "rotate service account token")
+		}
+		d.Set("token", v.Token)
+	} else if d.HasChange("name") {
+		_, _, err := client.ServiceAccounts.PatchToken(ctx, prj, sa, d.Id(), &gometakube.PatchTokenRequest{
+			Name: d.Get("name").(string),
+		})
+		if err != nil {
+			return errors.Wrap(err, "patch service account token")
+		}
+	}
+	return resourceServiceAccountTokenRead(d, m)
+}
+
+func resourceServiceAccountTokenDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := operationContext(d, m, schema.TimeoutDelete)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	prj := d.Get("project_id").(string)
+	sa := d.Get("service_account_id").(string)
+	_, err := client.ServiceAccounts.DeleteToken(ctx, prj, sa, d.Id())
+	return err
+}
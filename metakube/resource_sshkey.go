@@ -1,7 +1,6 @@
 package metakube
 
 import (
-	"context"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -16,6 +15,15 @@ func resourceSSHKey() *schema.Resource {
 		Read:   resourceSSHKeyRead,
 		Delete: resourceSSHKeyDelete,
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceSSHKeyV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceSSHKeyStateUpgradeV0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:         schema.TypeString,
@@ -31,35 +39,72 @@ func resourceSSHKey() *schema.Resource {
 			},
 			"public_key": {
 				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.NoZeroValues,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateSSHPublicKey,
 				DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
-				ForceNew: true,
+				ForceNew:      true,
+				ConflictsWith: []string{"generate"},
+			},
+			"generate": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Description:   "Generate ed25519 keypair, private key is kept in state.",
+				ConflictsWith: []string{"public_key"},
+			},
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
+			"private_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+		CustomizeDiff: func(d *schema.ResourceDiff, _ interface{}) error {
+			if d.Id() == "" && !d.Get("generate").(bool) && d.Get("public_key").(string) == "" && d.NewValueKnown("public_key") {
+				return errors.New("one of public_key or generate must be set")
+			}
+			return nil
 		},
 	}
 }
 
 func resourceSSHKeyCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*gometakube.Client)
-	v, _, err := client.SSHKeys.Create(context.Background(), d.Get("project_id").(string), &gometakube.SSHKey{
+	ctx, cancel := operationContext(d, m, schema.TimeoutCreate)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	publicKey := d.Get("public_key").(string)
+	if d.Get("generate").(bool) {
+		pub, priv, err := generateSSHKey(d.Get("name").(string))
+		if err != nil {
+			return err
+		}
+		publicKey = pub
+		d.Set("private_key", priv)
+	}
+	v, _, err := client.SSHKeys.Create(ctx, d.Get("project_id").(string), &gometakube.SSHKey{
 		Name: d.Get("name").(string),
 		Spec: gometakube.SSHKeySpec{
-			PublicKey: d.Get("public_key").(string),
+			PublicKey: publicKey,
 		},
 	})
 	if err != nil {
 		return err
 	}
 	d.SetId(v.ID)
-	return nil
+	return resourceSSHKeyRead(d, m)
 }
 
 func resourceSSHKeyRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*gometakube.Client)
-	sshkeys, _, err := client.SSHKeys.List(context.Background(), d.Get("project_id").(string))
+	ctx, cancel := operationContext(d, m, schema.TimeoutRead)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	sshkeys, _, err := client.SSHKeys.List(ctx, d.Get("project_id").(string))
 	if err != nil {
 		return errors.Wrap(err, "list sshkeys")
 	}
@@ -76,11 +121,18 @@ func resourceSSHKeyRead(d *schema.ResourceData, m interface{}) error {
 	}
 	d.Set("name", v.Name)
 	d.Set("public_key", v.Spec.PublicKey)
+	if v.Spec.Fingerprint != nil {
+		d.Set("fingerprint", *v.Spec.Fingerprint)
+	} else if fingerprint, err := sshKeyFingerprint(v.Spec.PublicKey); err == nil {
+		d.Set("fingerprint", fingerprint)
+	}
 	return nil
 }
 
 func resourceSSHKeyDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*gometakube.Client)
-	_, err := client.SSHKeys.Delete(context.Background(), d.Get("project_id").(string), d.Id())
+	ctx, cancel := operationContext(d, m, schema.TimeoutDelete)
+	defer cancel()
+	client := m.(*metakubeProviderMeta).client
+	_, err := client.SSHKeys.Delete(ctx, d.Get("project_id").(string), d.Id())
 	return err
 }
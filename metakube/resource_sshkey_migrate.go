@@ -0,0 +1,36 @@
+package metakube
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceSSHKeyV0 is the shape of ssh key state written before schema versions were introduced.
+func resourceSSHKeyV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"public_key": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+// resourceSSHKeyStateUpgradeV0 trims public key, older versions stored it as configured,
+// with trailing new line of files read with file().
+func resourceSSHKeyStateUpgradeV0(rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if v, ok := rawState["public_key"].(string); ok {
+		rawState["public_key"] = strings.TrimSpace(v)
+	}
+	return rawState, nil
+}
@@ -0,0 +1,16 @@
+package metakube
+
+import (
+	"testing"
+)
+
+func TestResourceSSHKeyStateUpgradeV0(t *testing.T) {
+	publicKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAILmQoVCn0J1zaclM3+jPB9lL7MOnA/SbLZvGuUXDrH+m user@machine"
+	got, err := resourceSSHKeyStateUpgradeV0(map[string]interface{}{"public_key": publicKey + "\n"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := publicKey; got["public_key"] != want {
+		t.Fatalf("want public key %q, got %q", want, got["public_key"])
+	}
+}
@@ -1,6 +1,7 @@
 package metakube
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -23,6 +24,22 @@ resource "metakube_sshkey" "test-sshkey" {
 	name = "my-key"
 	public_key = "` + testSSHPubKey + `"
 }
+`
+	testAccSSHKeyConfigGenerate = `
+provider "metakube" {
+}
+
+resource "metakube_project" "sshkey-project" {
+	name = "foo"
+	labels = {}
+}
+
+resource "metakube_sshkey" "test-sshkey" {
+	project_id = metakube_project.sshkey-project.id
+
+	name = "generated-key"
+	generate = true
+}
 `
 )
 
@@ -39,6 +56,27 @@ func TestAccMetakubeSSHKey_Basic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("metakube_sshkey.test-sshkey", "name", "my-key"),
 					resource.TestCheckResourceAttr("metakube_sshkey.test-sshkey", "public_key", testSSHPubKey),
+					resource.TestCheckResourceAttrSet("metakube_sshkey.test-sshkey", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMetakubeSSHKey_Generate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testEnvSet(t, APITokenEnvName)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetakubeProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSSHKeyConfigGenerate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("metakube_sshkey.test-sshkey", "public_key", regexp.MustCompile("^ssh-ed25519 ")),
+					resource.TestMatchResourceAttr("metakube_sshkey.test-sshkey", "private_key", regexp.MustCompile("OPENSSH PRIVATE KEY")),
+					resource.TestCheckResourceAttrSet("metakube_sshkey.test-sshkey", "fingerprint"),
 				),
 			},
 		},
@@ -0,0 +1,124 @@
+package metakube
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshKeyTypes are public key types accepted in authorized_keys format.
+var sshKeyTypes = []string{
+	ssh.KeyAlgoRSA,
+	ssh.KeyAlgoED25519,
+	ssh.KeyAlgoECDSA256,
+	ssh.KeyAlgoECDSA384,
+	ssh.KeyAlgoECDSA521,
+}
+
+func parseSSHPublicKey(v string) (ssh.PublicKey, error) {
+	key, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(v)))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse ssh public key")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("expected single ssh public key")
+	}
+	for _, t := range sshKeyTypes {
+		if key.Type() == t {
+			return key, nil
+		}
+	}
+	return nil, errors.Errorf("unsupported ssh key type `%s`, want one of: %s", key.Type(), strings.Join(sshKeyTypes, ", "))
+}
+
+func validateSSHPublicKey(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{errors.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseSSHPublicKey(v); err != nil {
+		return nil, []error{errors.Wrap(err, k)}
+	}
+	return nil, nil
+}
+
+// sshKeyFingerprint returns MD5 fingerprint, the format MetaKube uses.
+func sshKeyFingerprint(v string) (string, error) {
+	key, err := parseSSHPublicKey(v)
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintLegacyMD5(key), nil
+}
+
+// generateSSHKey returns ed25519 public key in authorized_keys format and private key in OpenSSH PEM format.
+func generateSSHKey(comment string) (string, string, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", errors.Wrap(err, "generate ed25519 key")
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return "", "", err
+	}
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if comment != "" {
+		authorized += " " + comment
+	}
+	block, err := marshalED25519PrivateKey(priv, sshPub, comment)
+	if err != nil {
+		return "", "", err
+	}
+	return authorized, string(pem.EncodeToMemory(block)), nil
+}
+
+// marshalED25519PrivateKey encodes key in openssh-key-v1 format, unencrypted.
+// See PROTOCOL.key in OpenSSH sources.
+func marshalED25519PrivateKey(priv ed25519.PrivateKey, pub ssh.PublicKey, comment string) (*pem.Block, error) {
+	var check [4]byte
+	if _, err := rand.Read(check[:]); err != nil {
+		return nil, errors.Wrap(err, "generate check bytes")
+	}
+	checkInt := binary.BigEndian.Uint32(check[:])
+	privBlock := ssh.Marshal(struct {
+		Check1  uint32
+		Check2  uint32
+		Keytype string
+		Pub     []byte
+		Priv    []byte
+		Comment string
+	}{
+		Check1:  checkInt,
+		Check2:  checkInt,
+		Keytype: ssh.KeyAlgoED25519,
+		Pub:     priv.Public().(ed25519.PublicKey),
+		Priv:    priv,
+		Comment: comment,
+	})
+	for i := byte(1); len(privBlock)%8 != 0; i++ {
+		privBlock = append(privBlock, i)
+	}
+	data := ssh.Marshal(struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{
+		CipherName:   "none",
+		KdfName:      "none",
+		NumKeys:      1,
+		PubKey:       pub.Marshal(),
+		PrivKeyBlock: privBlock,
+	})
+	return &pem.Block{
+		Type:  "OPENSSH PRIVATE KEY",
+		Bytes: append([]byte("openssh-key-v1\x00"), data...),
+	}, nil
+}
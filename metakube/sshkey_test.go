@@ -0,0 +1,54 @@
+package metakube
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseSSHPublicKey(t *testing.T) {
+	for _, tc := range []struct {
+		key     string
+		wantErr bool
+	}{
+		{key: testSSHPubKey},
+		{key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAILmQoVCn0J1zaclM3+jPB9lL7MOnA/SbLZvGuUXDrH+m user@machine\n"},
+		{key: "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBHx5ZUJsDpbqcqUOjqYOcvvsLhCqA3Fsnp1vcEfvf5aI9CU6w3+K++dsaV/vHqe4a/4E6j4mEUxiNz9NVMui2Yw="},
+		{key: "ssh-rsa AAAAnotakey", wantErr: true},
+		{key: "not a key", wantErr: true},
+		{key: "", wantErr: true},
+	} {
+		_, err := parseSSHPublicKey(tc.key)
+		if tc.wantErr != (err != nil) {
+			t.Fatalf("%q: want error %v, got %v", tc.key, tc.wantErr, err)
+		}
+	}
+}
+
+func TestSSHKeyFingerprint(t *testing.T) {
+	got, err := sshKeyFingerprint("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAILmQoVCn0J1zaclM3+jPB9lL7MOnA/SbLZvGuUXDrH+m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a3:6a:d6:66:38:6c:89:fb:6b:58:00:b0:a1:e9:25:30"; want != got {
+		t.Fatalf("want fingerprint: %s, got: %s", want, got)
+	}
+}
+
+func TestGenerateSSHKey(t *testing.T) {
+	pub, priv, err := generateSSHKey("user@machine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := parseSSHPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(priv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := string(pubKey.Marshal()), string(signer.PublicKey().Marshal()); want != got {
+		t.Fatal("generated private key does not match public key")
+	}
+}